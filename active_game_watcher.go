@@ -0,0 +1,245 @@
+package ionia
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ActiveGameEventType is the kind of change observed by an ActiveGameWatcher.
+type ActiveGameEventType int
+
+// Active game event types.
+const (
+	// GameEntered is sent when one or more watched summoners are found in a new game.
+	GameEntered ActiveGameEventType = iota
+
+	// GameLeft is sent when a game containing watched summoners is no longer active.
+	GameLeft
+
+	// WatchError is sent when looking up a summoner's current game fails.
+	// The summoner's previous state is kept until a lookup succeeds.
+	WatchError
+)
+
+func (t ActiveGameEventType) String() string {
+	switch t {
+	case GameEntered:
+		return "GameEntered"
+	case GameLeft:
+		return "GameLeft"
+	case WatchError:
+		return "WatchError"
+	}
+	return fmt.Sprintf("ActiveGameEventType(%d)", int(t))
+}
+
+// ActiveGameEvent describes a change in the games of watched summoners.
+// Watched summoners playing in the same game share a single event.
+type ActiveGameEvent struct {
+	Type   ActiveGameEventType
+	GameID int64
+
+	// The watched summoners in the game, or the summoner whose lookup failed.
+	SummonerIDs []int64
+
+	// The game as last seen by the watcher. This is not set for WatchError events.
+	Game *CurrentGameInfo
+
+	// The error which occurred. This is only set for WatchError events.
+	Err error
+}
+
+// ActiveGameWatcherOptions specifies the optional parameters for an ActiveGameWatcher.
+type ActiveGameWatcherOptions struct {
+	// The maximum number of current game requests made per second.
+	// Default: 1.
+	RequestsPerSecond float64
+
+	// The minimum time between the start of two sweeps of the roster.
+	// Sweeps of large rosters take longer than this, as they are limited by RequestsPerSecond.
+	// Default: 1 minute.
+	SweepInterval time.Duration
+}
+
+// ActiveGameWatcherOption is a function which modifies the ActiveGameWatcherOptions.
+type ActiveGameWatcherOption func(*ActiveGameWatcherOptions)
+
+// ActiveGameWatcher repeatedly looks up the current game of a roster of summoners
+// and reports when they enter and leave games.
+//
+// A summoner found in the game of another watched summoner is not looked up
+// separately during the same sweep, so a game of several watched summoners
+// costs a single request.
+type ActiveGameWatcher struct {
+	client  *Client
+	options ActiveGameWatcherOptions
+
+	mu     sync.Mutex
+	roster map[int64]bool
+
+	// Games known to contain watched summoners, keyed by game ID.
+	games map[int64]*watchedGame
+}
+
+type watchedGame struct {
+	info    *CurrentGameInfo
+	members []int64
+}
+
+// NewActiveGameWatcher creates a watcher for the given summoner IDs.
+func NewActiveGameWatcher(c *Client, summonerIDs []int64, opts ...ActiveGameWatcherOption) *ActiveGameWatcher {
+	options := ActiveGameWatcherOptions{
+		RequestsPerSecond: 1,
+		SweepInterval:     time.Minute,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	w := &ActiveGameWatcher{
+		client:  c,
+		options: options,
+		roster:  make(map[int64]bool),
+		games:   make(map[int64]*watchedGame),
+	}
+	w.Add(summonerIDs...)
+	return w
+}
+
+// Add adds summoners to the roster. They are looked up from the next sweep.
+func (w *ActiveGameWatcher) Add(summonerIDs ...int64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, id := range summonerIDs {
+		w.roster[id] = true
+	}
+}
+
+// Remove removes summoners from the roster.
+// Games they are in are not reported as left until the next sweep.
+func (w *ActiveGameWatcher) Remove(summonerIDs ...int64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, id := range summonerIDs {
+		delete(w.roster, id)
+	}
+}
+
+// Run sweeps the roster until ctx is done, sending events to the given channel.
+// Run always returns a non-nil error, which is ctx.Err() once the context is done.
+func (w *ActiveGameWatcher) Run(ctx context.Context, events chan<- ActiveGameEvent) error {
+	rps := w.options.RequestsPerSecond
+	if rps <= 0 {
+		rps = 1
+	}
+	limiter := time.NewTicker(time.Duration(float64(time.Second) / rps))
+	defer limiter.Stop()
+
+	for {
+		start := time.Now()
+		if err := w.sweep(ctx, limiter.C, events); err != nil {
+			return err
+		}
+
+		t := time.NewTimer(w.options.SweepInterval - time.Since(start))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// sweep looks up every summoner in the roster once and reports any changes.
+func (w *ActiveGameWatcher) sweep(ctx context.Context, limiter <-chan time.Time, events chan<- ActiveGameEvent) error {
+	w.mu.Lock()
+	roster := make([]int64, 0, len(w.roster))
+	for id := range w.roster {
+		roster = append(roster, id)
+	}
+	w.mu.Unlock()
+	sort.Slice(roster, func(i, j int) bool { return roster[i] < roster[j] })
+
+	inRoster := make(map[int64]bool, len(roster))
+	for _, id := range roster {
+		inRoster[id] = true
+	}
+
+	games := make(map[int64]*watchedGame)
+	covered := make(map[int64]bool)
+	for _, id := range roster {
+		if covered[id] {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-limiter:
+		}
+
+		game, resp, err := w.client.Spectator.CurrentGame(id)
+		covered[id] = true
+		switch {
+		case resp != nil && resp.StatusCode == http.StatusNotFound:
+			// The summoner is not in a game.
+		case err == nil && resp != nil && resp.StatusCode == http.StatusOK:
+			wg := &watchedGame{info: game}
+			for _, p := range game.Participants {
+				if inRoster[p.SummonerID] {
+					covered[p.SummonerID] = true
+					wg.members = append(wg.members, p.SummonerID)
+				}
+			}
+			games[game.GameID] = wg
+		default:
+			err = checkResponse(resp, err)
+			sendActiveGameEvent(ctx, events, ActiveGameEvent{Type: WatchError, SummonerIDs: []int64{id}, Err: err})
+
+			// Keep whichever game the summoner was last seen in.
+			for gameID, g := range w.games {
+				if containsSummoner(g.members, id) {
+					games[gameID] = g
+					for _, m := range g.members {
+						covered[m] = true
+					}
+				}
+			}
+		}
+	}
+
+	for gameID, g := range games {
+		if _, ok := w.games[gameID]; !ok {
+			sendActiveGameEvent(ctx, events, ActiveGameEvent{Type: GameEntered, GameID: gameID, SummonerIDs: g.members, Game: g.info})
+		}
+	}
+	for gameID, g := range w.games {
+		if _, ok := games[gameID]; !ok {
+			sendActiveGameEvent(ctx, events, ActiveGameEvent{Type: GameLeft, GameID: gameID, SummonerIDs: g.members, Game: g.info})
+		}
+	}
+	w.games = games
+
+	return nil
+}
+
+func containsSummoner(ids []int64, id int64) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+func sendActiveGameEvent(ctx context.Context, events chan<- ActiveGameEvent, e ActiveGameEvent) {
+	select {
+	case events <- e:
+	case <-ctx.Done():
+	}
+}
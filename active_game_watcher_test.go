@@ -0,0 +1,71 @@
+package ionia
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestActiveGameWatcher(t *testing.T) {
+	client, mux, _, teardown := createTestServer()
+	defer teardown()
+
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	inGame := true
+	mux.HandleFunc("/lol/spectator/v3/active-games/by-summoner/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests[r.URL.Path]++
+		if r.URL.Path == "/lol/spectator/v3/active-games/by-summoner/3" || !inGame {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"gameId": 50, "participants": [{"summonerId": 1}, {"summonerId": 2}, {"summonerId": 9}]}`)
+	})
+
+	wa := NewActiveGameWatcher(client, []int64{1, 2, 3}, func(o *ActiveGameWatcherOptions) {
+		o.RequestsPerSecond = 1000
+		o.SweepInterval = time.Millisecond
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events := make(chan ActiveGameEvent)
+	done := make(chan error)
+	go func() { done <- wa.Run(ctx, events) }()
+
+	select {
+	case e := <-events:
+		if e.Type != GameEntered || e.GameID != 50 || !reflect.DeepEqual(e.SummonerIDs, []int64{1, 2}) {
+			t.Fatalf("unexpected event: %+v", e)
+		}
+		mu.Lock()
+		if n := requests["/lol/spectator/v3/active-games/by-summoner/2"]; n != 0 {
+			t.Errorf("expected summoner in a known game not to be looked up, got %d requests", n)
+		}
+		inGame = false
+		mu.Unlock()
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for GameEntered")
+	}
+
+	select {
+	case e := <-events:
+		if e.Type != GameLeft || e.GameID != 50 {
+			t.Fatalf("unexpected event: %+v", e)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for GameLeft")
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run returned %v, want %v", err, context.Canceled)
+	}
+}
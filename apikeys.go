@@ -0,0 +1,107 @@
+package ionia
+
+import (
+	"fmt"
+	"hash/fnv"
+	"time"
+)
+
+// apiKey is a Riot API key in a Client's pool.
+type apiKey struct {
+	key string
+	id  string
+}
+
+func newAPIKeys(keys []string) []apiKey {
+	if len(keys) == 0 {
+		keys = []string{""}
+	}
+	pool := make([]apiKey, len(keys))
+	for i, k := range keys {
+		pool[i] = apiKey{key: k, id: APIKeyID(k)}
+	}
+	return pool
+}
+
+// APIKeyID returns the identifier under which the rate limits of an API key are
+// tracked, as reported in RateLimitStatus and RateLimitEvent. It does not reveal
+// the key, so it can be logged or stored.
+func APIKeyID(key string) string {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
+// WithAPIKeys returns a ClientOption which sets a pool of API keys, replacing the
+// key given to NewClient. Each request is sent with the key which has the most
+// headroom in its rate limits for the request's platform and method.
+func WithAPIKeys(keys ...string) ClientOption {
+	return func(c *Client) {
+		c.keys = newAPIKeys(keys)
+	}
+}
+
+// SetAPIKeys replaces the Client's API keys, for example when a development key
+// expires. Rate limits already seen for keys which remain in the pool are kept.
+// It is safe to call while requests are being made.
+func (c *Client) SetAPIKeys(keys ...string) {
+	pool := newAPIKeys(keys)
+	c.keyMu.Lock()
+	c.keys = pool
+	c.keyMu.Unlock()
+}
+
+// apiKeys returns the Client's current pool of keys.
+func (c *Client) apiKeys() []apiKey {
+	c.keyMu.Lock()
+	defer c.keyMu.Unlock()
+	return c.keys
+}
+
+// pickKey returns the key with the most headroom for a platform and method,
+// measured as the smallest fraction of requests remaining in any of its
+// application or method windows. Ties go to the earliest key.
+func (c *Client) pickKey(platform, method string) apiKey {
+	keys := c.apiKeys()
+	if len(keys) == 1 {
+		return keys[0]
+	}
+
+	now := time.Now()
+	best, bestHeadroom := keys[0], -1.0
+	for _, k := range keys {
+		headroom := c.headroom([]string{limitKey(k.id, platform, appRateMethod), limitKey(k.id, platform, method)}, now)
+		if headroom > bestHeadroom {
+			best, bestHeadroom = k, headroom
+		}
+	}
+	return best
+}
+
+// headroom returns the smallest fraction of requests remaining in any window of
+// the keys. It uses the counts of the rate limit store if it is a RateLimitPeeker,
+// since they include requests the Riot API has not reported yet, and otherwise
+// the counts last reported. Limits which have not been seen yet, or whose windows
+// have reset, count as full headroom.
+func (c *Client) headroom(keys []string, now time.Time) float64 {
+	if p, ok := c.rateStore.(RateLimitPeeker); ok {
+		if h, err := p.Headroom(keys, now); err == nil {
+			return h
+		}
+	}
+
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+	headroom := 1.0
+	for _, key := range keys {
+		for _, w := range c.windows(key, c.rateLimits[key]) {
+			if w.Allowed <= 0 || !now.Before(w.Reset) {
+				continue
+			}
+			if h := float64(w.Remaining()) / float64(w.Allowed); h < headroom {
+				headroom = h
+			}
+		}
+	}
+	return headroom
+}
@@ -0,0 +1,118 @@
+package ionia
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestAPIKeys(t *testing.T) {
+	client, mux, _, teardown := createTestServer()
+	defer teardown()
+	WithAPIKeys("RGAPI-dev", "RGAPI-prod")(client)
+
+	var sent []string
+	used := make(map[string]int)
+	allowed := map[string]int{"RGAPI-dev": 2, "RGAPI-prod": 10, "RGAPI-new": 2}
+	mux.HandleFunc("/lol/summoner/v3/summoners/1", func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(headerRiotToken)
+		sent = append(sent, key)
+		used[key]++
+		w.Header().Set(headerAppRateLimit, fmt.Sprintf("%d:120", allowed[key]))
+		w.Header().Set(headerAppRateLimitCount, fmt.Sprintf("%d:120", used[key]))
+		fmt.Fprint(w, `{"id": 1}`)
+	})
+
+	for i := 0; i < 5; i++ {
+		if _, _, err := client.Summoner.BySummonerID(1); err != nil {
+			t.Fatalf("Summoner.BySummonerID returned error: %v", err)
+		}
+	}
+	// The dev key is used first, then whichever key has the larger fraction remaining.
+	want := []string{"RGAPI-dev", "RGAPI-prod", "RGAPI-prod", "RGAPI-prod", "RGAPI-prod"}
+	if !reflect.DeepEqual(sent, want) {
+		t.Errorf("unexpected keys: got %v, want %v", sent, want)
+	}
+
+	var ids []string
+	for _, s := range client.RateLimits() {
+		if s.Method == "" {
+			ids = append(ids, s.Key)
+		}
+	}
+	wantIDs := []string{APIKeyID("RGAPI-dev"), APIKeyID("RGAPI-prod")}
+	sort.Strings(wantIDs)
+	if !reflect.DeepEqual(ids, wantIDs) {
+		t.Errorf("expected app limits for both keys, got %v", ids)
+	}
+	for _, id := range ids {
+		if strings.Contains(id, "RGAPI") {
+			t.Errorf("key ID %q reveals the key", id)
+		}
+	}
+
+	// The dev key expires and is swapped for a new one.
+	client.SetAPIKeys("RGAPI-new")
+	sent = nil
+	client.Summoner.BySummonerID(1)
+	if !reflect.DeepEqual(sent, []string{"RGAPI-new"}) {
+		t.Errorf("expected new key to be used, got %v", sent)
+	}
+	if got := client.redact("key RGAPI-new"); got != "key "+redactedValue {
+		t.Errorf("expected new key to be redacted, got %q", got)
+	}
+}
+
+func TestAPIKeysExhausted(t *testing.T) {
+	client, mux, _, teardown := createTestServer()
+	defer teardown()
+	WithAPIKeys("RGAPI-a", "RGAPI-b")(client)
+
+	var sent []string
+	mux.HandleFunc("/lol/summoner/v3/summoners/1", func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.Header.Get(headerRiotToken))
+		w.Header().Set(headerAppRateLimit, "1:120")
+		w.Header().Set(headerAppRateLimitCount, "1:120")
+		fmt.Fprint(w, `{"id": 1}`)
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := client.Summoner.BySummonerID(1); err != nil {
+			t.Fatalf("Summoner.BySummonerID returned error: %v", err)
+		}
+	}
+	if !reflect.DeepEqual(sent, []string{"RGAPI-a", "RGAPI-b"}) {
+		t.Errorf("expected each key to be used once, got %v", sent)
+	}
+	if _, resp, _ := client.Summoner.BySummonerID(1); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected 403 once both keys are exhausted, got %d", resp.StatusCode)
+	}
+}
+
+func TestAPIKeysLocalCounts(t *testing.T) {
+	client, mux, _, teardown := createTestServer()
+	defer teardown()
+	WithAPIKeys("RGAPI-a", "RGAPI-b")(client)
+
+	var sent []string
+	mux.HandleFunc("/lol/summoner/v3/summoners/1", func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.Header.Get(headerRiotToken))
+		// The reported counts lag behind the requests sent.
+		w.Header().Set(headerAppRateLimit, "10:120")
+		w.Header().Set(headerAppRateLimitCount, "1:120")
+		fmt.Fprint(w, `{"id": 1}`)
+	})
+
+	for i := 0; i < 4; i++ {
+		if _, _, err := client.Summoner.BySummonerID(1); err != nil {
+			t.Fatalf("Summoner.BySummonerID returned error: %v", err)
+		}
+	}
+	want := []string{"RGAPI-a", "RGAPI-b", "RGAPI-a", "RGAPI-b"}
+	if !reflect.DeepEqual(sent, want) {
+		t.Errorf("unexpected keys: got %v, want %v", sent, want)
+	}
+}
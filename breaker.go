@@ -0,0 +1,232 @@
+package ionia
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// BreakerState is the state of a circuit in a Breaker.
+type BreakerState int
+
+// Breaker states.
+const (
+	// BreakerClosed lets requests through.
+	BreakerClosed BreakerState = iota
+
+	// BreakerOpen fails requests without sending them.
+	BreakerOpen
+
+	// BreakerHalfOpen lets a single request through to probe whether the
+	// endpoint has recovered.
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("BreakerState(%d)", int(s))
+}
+
+// CircuitOpenError is returned by Client.Do for requests which were not sent
+// because the circuit for their platform and method is open.
+type CircuitOpenError struct {
+	Platform string
+	Method   string
+
+	// When the circuit half-opens.
+	Until time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit open for %s on %s until %s", e.Method, e.Platform, e.Until.Format(time.RFC3339))
+}
+
+// BreakerOption is a function which modifies the BreakerOptions.
+type BreakerOption func(*BreakerOptions)
+
+// BreakerOptions specifies the optional parameters for NewBreaker.
+type BreakerOptions struct {
+	// The number of consecutive failures which open a circuit. Default: 5
+	Threshold int
+
+	// How long a circuit stays open before it half-opens. Default: 30 seconds
+	Cooldown time.Duration
+
+	// IsFailure reports whether a request failed. Default: a transport error,
+	// or a 500, 502, 503 or 504 response.
+	IsFailure func(resp *http.Response, err error) bool
+}
+
+// Breaker is a circuit breaker for the requests of the Clients it is attached
+// to with WithBreaker. Each platform and method, such as na1 GET_getMatch, has
+// its own circuit, which opens after consecutive failures so that a degraded
+// endpoint is not sent requests which would only use up the rate limits.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+	isFailure func(*http.Response, error) bool
+	now       func() time.Time
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+type circuit struct {
+	failures int
+	// The circuit is open until this time, after which it is half-open.
+	until   time.Time
+	open    bool
+	probing bool
+}
+
+// NewBreaker creates a Breaker with every circuit closed.
+func NewBreaker(opts ...BreakerOption) *Breaker {
+	options := &BreakerOptions{
+		Threshold: 5,
+		Cooldown:  30 * time.Second,
+		IsFailure: func(resp *http.Response, err error) bool {
+			if err != nil {
+				return true
+			}
+			switch resp.StatusCode {
+			case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+				return true
+			}
+			return false
+		},
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return &Breaker{
+		threshold: options.Threshold,
+		cooldown:  options.Cooldown,
+		isFailure: options.IsFailure,
+		now:       time.Now,
+		circuits:  make(map[string]*circuit),
+	}
+}
+
+// WithBreaker returns a ClientOption which fails the Client's requests fast
+// while their circuit in b is open.
+func WithBreaker(b *Breaker) ClientOption {
+	return func(c *Client) {
+		c.breaker = b
+	}
+}
+
+// State returns the state of the circuit for a platform and method.
+// A method of "" returns the state of the circuit for the whole platform.
+func (b *Breaker) State(platform, method string) BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state(b.circuits[rateKey(platform, method)])
+}
+
+func (b *Breaker) state(c *circuit) BreakerState {
+	switch {
+	case c == nil || !c.open:
+		return BreakerClosed
+	case b.now().Before(c.until):
+		return BreakerOpen
+	}
+	return BreakerHalfOpen
+}
+
+// Trip opens the circuit for a platform and method for d. A method of "" opens
+// a circuit covering every method on the platform.
+func (b *Breaker) Trip(platform, method string, d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := b.circuit(rateKey(platform, method))
+	c.open, c.until, c.probing = true, b.now().Add(d), false
+}
+
+// TripIncidents opens the circuit for every method on platform for d if the
+// shard status, as returned by StatusService.ShardData, has an active incident
+// whose latest update is more severe than "info". It reports whether the
+// circuit was opened.
+func (b *Breaker) TripIncidents(platform string, ss *ShardStatus, d time.Duration) bool {
+	for _, s := range ss.Services {
+		for _, i := range s.Incidents {
+			if !i.Active || len(i.Updates) == 0 {
+				continue
+			}
+			if i.Updates[len(i.Updates)-1].Severity != "info" {
+				b.Trip(platform, "", d)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (b *Breaker) circuit(key string) *circuit {
+	c, ok := b.circuits[key]
+	if !ok {
+		c = &circuit{}
+		b.circuits[key] = c
+	}
+	return c
+}
+
+// allow returns a CircuitOpenError if a request for the platform and method
+// must not be sent. Otherwise, the result of the request must be passed to
+// done, or nil and nil if it was not sent.
+func (b *Breaker) allow(platform, method string) (done func(*http.Response, error), err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var probes []*circuit
+	for _, key := range []string{rateKey(platform, ""), rateKey(platform, method)} {
+		c := b.circuits[key]
+		switch b.state(c) {
+		case BreakerOpen:
+			return nil, &CircuitOpenError{Platform: platform, Method: method, Until: c.until}
+		case BreakerHalfOpen:
+			if c.probing {
+				return nil, &CircuitOpenError{Platform: platform, Method: method, Until: c.until}
+			}
+			probes = append(probes, c)
+		}
+	}
+	for _, c := range probes {
+		c.probing = true
+	}
+
+	return func(resp *http.Response, err error) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for _, c := range probes {
+			c.probing = false
+		}
+		if resp == nil && err == nil {
+			return
+		}
+
+		c := b.circuit(rateKey(platform, method))
+		if !b.isFailure(resp, err) {
+			c.failures = 0
+			c.open = false
+			for _, p := range probes {
+				p.open = false
+			}
+			return
+		}
+		c.failures++
+		if c.failures >= b.threshold || len(probes) > 0 {
+			c.open, c.until = true, b.now().Add(b.cooldown)
+			for _, p := range probes {
+				p.until = b.now().Add(b.cooldown)
+			}
+		}
+	}, nil
+}
@@ -0,0 +1,121 @@
+package ionia
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Unix(1527000000, 0)
+	b := NewBreaker(func(o *BreakerOptions) {
+		o.Threshold = 2
+		o.Cooldown = 10 * time.Second
+	})
+	b.now = func() time.Time { return now }
+
+	unavailable := &http.Response{StatusCode: http.StatusServiceUnavailable}
+	ok := &http.Response{StatusCode: http.StatusOK}
+	send := func(resp *http.Response, err error) error {
+		done, openErr := b.allow("na1", "GET_getMatch")
+		if openErr != nil {
+			return openErr
+		}
+		done(resp, err)
+		return nil
+	}
+
+	if err := send(unavailable, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := send(nil, errors.New("connection reset")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s := b.State("na1", "GET_getMatch"); s != BreakerOpen {
+		t.Fatalf("expected open circuit after 2 failures, got %s", s)
+	}
+	err := send(ok, nil)
+	if e, isOpen := err.(*CircuitOpenError); !isOpen || e.Method != "GET_getMatch" || !e.Until.Equal(now.Add(10*time.Second)) {
+		t.Fatalf("expected CircuitOpenError, got %v", err)
+	}
+	if s := b.State("na1", "GET_getSummoner"); s != BreakerClosed {
+		t.Errorf("expected other methods to be closed, got %s", s)
+	}
+
+	now = now.Add(10 * time.Second)
+	if s := b.State("na1", "GET_getMatch"); s != BreakerHalfOpen {
+		t.Fatalf("expected half-open circuit after the cooldown, got %s", s)
+	}
+	probe, err := b.allow("na1", "GET_getMatch")
+	if err != nil {
+		t.Fatalf("expected probe to be allowed, got %v", err)
+	}
+	if _, err := b.allow("na1", "GET_getMatch"); err == nil {
+		t.Error("expected requests to fail while probing")
+	}
+	probe(unavailable, nil)
+	if s := b.State("na1", "GET_getMatch"); s != BreakerOpen {
+		t.Fatalf("expected failed probe to reopen the circuit, got %s", s)
+	}
+
+	now = now.Add(10 * time.Second)
+	// A probe which was not sent lets the next request probe instead.
+	probe, _ = b.allow("na1", "GET_getMatch")
+	probe(nil, nil)
+	if err := send(ok, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s := b.State("na1", "GET_getMatch"); s != BreakerClosed {
+		t.Errorf("expected successful probe to close the circuit, got %s", s)
+	}
+}
+
+func TestBreakerTripIncidents(t *testing.T) {
+	b := NewBreaker()
+	ss := &ShardStatus{Services: []Service{{
+		Slug: "game",
+		Incidents: []Incident{
+			{Active: false, Updates: []Message{{Severity: "error"}}},
+			{Active: true, Updates: []Message{{Severity: "info"}}},
+		},
+	}}}
+	if b.TripIncidents("na1", ss, time.Minute) {
+		t.Error("expected no trip for resolved and informational incidents")
+	}
+
+	ss.Services[0].Incidents[1].Updates = append(ss.Services[0].Incidents[1].Updates, Message{Severity: "warn"})
+	if !b.TripIncidents("na1", ss, time.Minute) {
+		t.Fatal("expected trip for an active warning")
+	}
+	if _, err := b.allow("na1", "GET_getMatch"); err == nil {
+		t.Error("expected every method on the platform to fail")
+	}
+	if _, err := b.allow("euw1", "GET_getMatch"); err != nil {
+		t.Errorf("expected other platforms to be allowed, got %v", err)
+	}
+}
+
+func TestClientBreaker(t *testing.T) {
+	client, mux, _, teardown := createTestServer()
+	defer teardown()
+	WithBreaker(NewBreaker(func(o *BreakerOptions) { o.Threshold = 2 }))(client)
+
+	requests := 0
+	mux.HandleFunc("/lol/match/v3/matches/1", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	for i := 0; i < 3; i++ {
+		client.Match.MatchByID(1)
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests before the circuit opened, got %d", requests)
+	}
+	if _, _, err := client.Match.MatchByID(1); err == nil {
+		t.Error("expected CircuitOpenError, got nil")
+	} else if _, ok := err.(*CircuitOpenError); !ok {
+		t.Errorf("expected CircuitOpenError, got %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package ionia
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// WithCoalescing returns a ClientOption which makes concurrent identical GET
+// requests share a single request to the Riot API, and so a single request from
+// the rate limits. Requests are identical when their URLs are the same. Each
+// caller decodes its own copy of the response.
+func WithCoalescing() ClientOption {
+	return func(c *Client) {
+		c.coalescing = true
+		c.flights = make(map[string]*flight)
+	}
+}
+
+// flight is a request shared by the callers of identical requests.
+type flight struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+
+	// The number of callers waiting for the response. flightMu must be held.
+	waiters int
+
+	resp   *http.Response
+	body   []byte
+	denied bool
+	err    error
+}
+
+func coalescable(req *http.Request) bool {
+	return req.Method == http.MethodGet && (req.Body == nil || req.Body == http.NoBody)
+}
+
+// coalesce sends req, or waits for an identical request which is already in
+// flight, and returns a copy of the shared response.
+//
+// The shared request is not tied to the context of the caller which started it,
+// so that the other callers still get a response if that caller gives up. Each
+// caller waits until its own context is done, and the shared request is
+// canceled once no caller is waiting for it.
+func (c *Client) coalesce(req *http.Request, rateMethod string) (*http.Response, bool, error) {
+	key := req.Method + " " + req.URL.String()
+
+	c.flightMu.Lock()
+	f, ok := c.flights[key]
+	if !ok {
+		ctx, cancel := context.WithCancel(detachedContext{req.Context()})
+		f = &flight{done: make(chan struct{}), cancel: cancel}
+		c.flights[key] = f
+		go c.fly(key, f, req.WithContext(ctx), rateMethod)
+	}
+	f.waiters++
+	c.flightMu.Unlock()
+
+	select {
+	case <-f.done:
+	case <-req.Context().Done():
+		c.flightMu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			f.cancel()
+			if c.flights[key] == f {
+				delete(c.flights, key)
+			}
+		}
+		c.flightMu.Unlock()
+		return nil, false, req.Context().Err()
+	}
+
+	if f.resp == nil {
+		return nil, f.denied, f.err
+	}
+	resp := *f.resp
+	resp.Request = req
+	resp.Header = make(http.Header, len(f.resp.Header))
+	for k, v := range f.resp.Header {
+		resp.Header[k] = append([]string(nil), v...)
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(f.body))
+	return &resp, f.denied, f.err
+}
+
+// fly sends the shared request of f and reads its response.
+func (c *Client) fly(key string, f *flight, req *http.Request, rateMethod string) {
+	defer func() {
+		c.flightMu.Lock()
+		if c.flights[key] == f {
+			delete(c.flights, key)
+		}
+		c.flightMu.Unlock()
+		f.cancel()
+		close(f.done)
+	}()
+
+	f.resp, f.denied, f.err = c.do(req, rateMethod)
+	if f.resp != nil {
+		var err error
+		f.body, err = ioutil.ReadAll(f.resp.Body)
+		f.resp.Body.Close()
+		if err != nil && f.err == nil {
+			f.resp, f.err = nil, err
+		}
+	}
+}
+
+// detachedContext carries the values of a context, but not its deadline or
+// cancellation.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
@@ -0,0 +1,123 @@
+package ionia
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCoalescing(t *testing.T) {
+	client, mux, _, teardown := createTestServer()
+	defer teardown()
+	WithCoalescing()(client)
+
+	var mu sync.Mutex
+	requests := 0
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	mux.HandleFunc("/lol/summoner/v3/summoners/by-name/faker", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		started <- struct{}{}
+		<-release
+		fmt.Fprint(w, `{"id": 1, "name": "Faker"}`)
+	})
+
+	const callers = 5
+	summoners := make([]*SummonerDTO, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s, _, err := client.Summoner.BySummonerName("faker")
+			if err != nil {
+				t.Errorf("Summoner.BySummonerName returned error: %v", err)
+			}
+			summoners[i] = s
+		}(i)
+	}
+	<-started
+	// Give the other callers time to join the request in flight.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+	for i, s := range summoners {
+		if s == nil || s.Name != "Faker" {
+			t.Fatalf("caller %d got %+v", i, s)
+		}
+		for _, other := range summoners[:i] {
+			if s == other {
+				t.Errorf("caller %d shares its summoner with another caller", i)
+			}
+		}
+	}
+
+	// Requests which are not in flight at the same time are sent separately.
+	client.Summoner.BySummonerName("faker")
+	if requests != 2 {
+		t.Errorf("expected 2 requests, got %d", requests)
+	}
+}
+
+func TestCoalescingLeaderCanceled(t *testing.T) {
+	client, mux, _, teardown := createTestServer()
+	defer teardown()
+	WithCoalescing()(client)
+
+	var mu sync.Mutex
+	requests := 0
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	mux.HandleFunc("/lol/summoner/v3/summoners/by-name/faker", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		started <- struct{}{}
+		<-release
+		fmt.Fprint(w, `{"id": 1, "name": "Faker"}`)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := client.NewRequest(http.MethodGet, "lol/summoner/v3/summoners/by-name/faker", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leader := make(chan error, 1)
+	go func() {
+		_, err := client.Do(req.WithContext(ctx), new(SummonerDTO))
+		leader <- err
+	}()
+	<-started
+
+	follower := make(chan *SummonerDTO, 1)
+	go func() {
+		s, _, err := client.Summoner.BySummonerName("faker")
+		if err != nil {
+			t.Errorf("Summoner.BySummonerName returned error: %v", err)
+		}
+		follower <- s
+	}()
+	// Give the follower time to join the request in flight.
+	time.Sleep(50 * time.Millisecond)
+
+	cancel()
+	if err := <-leader; err != context.Canceled {
+		t.Errorf("expected the leader to get %v, got %v", context.Canceled, err)
+	}
+	close(release)
+	if s := <-follower; s == nil || s.Name != "Faker" {
+		t.Errorf("follower got %+v", s)
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+}
@@ -0,0 +1,202 @@
+package ionia
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// FeaturedGameEventType is the kind of change observed by a FeaturedGamesPoller.
+type FeaturedGameEventType int
+
+// Featured game event types.
+const (
+	// GameStarted is sent when a game appears in the featured games list.
+	GameStarted FeaturedGameEventType = iota
+
+	// GameEnded is sent when a game disappears from the featured games list.
+	GameEnded
+
+	// MatchAvailable is sent when the match for an ended game appears in match history.
+	MatchAvailable
+
+	// PollError is sent when a request made by the poller fails.
+	// The poller keeps running after an error.
+	PollError
+)
+
+func (t FeaturedGameEventType) String() string {
+	switch t {
+	case GameStarted:
+		return "GameStarted"
+	case GameEnded:
+		return "GameEnded"
+	case MatchAvailable:
+		return "MatchAvailable"
+	case PollError:
+		return "PollError"
+	}
+	return fmt.Sprintf("FeaturedGameEventType(%d)", int(t))
+}
+
+// FeaturedGameEvent describes a change to the featured games list.
+type FeaturedGameEvent struct {
+	Type   FeaturedGameEventType
+	GameID int64
+
+	// The featured game. This is set for GameStarted and GameEnded events,
+	// and for MatchAvailable events when the game was seen by the poller.
+	Game *FeaturedGameInfo
+
+	// The finished match. This is only set for MatchAvailable events.
+	Match *MatchDTO
+
+	// The error which occurred. This is only set for PollError events.
+	Err error
+}
+
+// FeaturedGamesPollerOptions specifies the optional parameters for a FeaturedGamesPoller.
+type FeaturedGamesPollerOptions struct {
+	// The refresh interval used when the API does not advertise one.
+	// Default: 5 minutes.
+	DefaultInterval time.Duration
+
+	// If true, ended games are looked up in match history on every refresh until
+	// the match is found or MatchAttempts is exhausted.
+	FetchMatches bool
+
+	// The number of refreshes on which to look for an ended game's match.
+	// Default: 6.
+	MatchAttempts int
+}
+
+// FeaturedGamesPollerOption is a function which modifies the FeaturedGamesPollerOptions.
+type FeaturedGamesPollerOption func(*FeaturedGamesPollerOptions)
+
+// FeaturedGamesPoller polls the featured games list at the interval advertised
+// by the API and reports games as they start and end.
+type FeaturedGamesPoller struct {
+	client  *Client
+	options FeaturedGamesPollerOptions
+
+	games   map[int64]FeaturedGameInfo
+	pending map[int64]*pendingMatch
+}
+
+type pendingMatch struct {
+	game     FeaturedGameInfo
+	attempts int
+}
+
+// NewFeaturedGamesPoller creates a poller which uses the given client.
+func NewFeaturedGamesPoller(c *Client, opts ...FeaturedGamesPollerOption) *FeaturedGamesPoller {
+	options := FeaturedGamesPollerOptions{
+		DefaultInterval: 5 * time.Minute,
+		MatchAttempts:   6,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return &FeaturedGamesPoller{
+		client:  c,
+		options: options,
+		games:   make(map[int64]FeaturedGameInfo),
+		pending: make(map[int64]*pendingMatch),
+	}
+}
+
+// Run polls the featured games list until ctx is done, sending events to the given channel.
+// Games already featured on the first refresh are reported as started.
+// Run always returns a non-nil error, which is ctx.Err() once the context is done.
+func (p *FeaturedGamesPoller) Run(ctx context.Context, events chan<- FeaturedGameEvent) error {
+	for {
+		interval := p.refresh(ctx, events)
+
+		t := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// refresh fetches the featured games once, sends any resulting events and
+// returns the time to wait before the next refresh.
+func (p *FeaturedGamesPoller) refresh(ctx context.Context, events chan<- FeaturedGameEvent) time.Duration {
+	interval := p.options.DefaultInterval
+
+	fg, resp, err := p.client.Spectator.FeaturedGames()
+	if err = checkResponse(resp, err); err != nil {
+		sendFeaturedGameEvent(ctx, events, FeaturedGameEvent{Type: PollError, Err: err})
+	} else {
+		if fg.ClientRefreshInterval > 0 {
+			interval = time.Duration(fg.ClientRefreshInterval) * time.Second
+		}
+		p.diff(ctx, fg.GameList, events)
+	}
+
+	if p.options.FetchMatches {
+		p.fetchMatches(ctx, events)
+	}
+
+	return interval
+}
+
+// diff compares the given game list to the previously seen one.
+func (p *FeaturedGamesPoller) diff(ctx context.Context, list []FeaturedGameInfo, events chan<- FeaturedGameEvent) {
+	current := make(map[int64]FeaturedGameInfo, len(list))
+	for _, g := range list {
+		current[g.GameID] = g
+		if _, ok := p.games[g.GameID]; !ok {
+			g := g
+			sendFeaturedGameEvent(ctx, events, FeaturedGameEvent{Type: GameStarted, GameID: g.GameID, Game: &g})
+		}
+	}
+
+	for id, g := range p.games {
+		if _, ok := current[id]; ok {
+			continue
+		}
+		g := g
+		sendFeaturedGameEvent(ctx, events, FeaturedGameEvent{Type: GameEnded, GameID: id, Game: &g})
+		if p.options.FetchMatches {
+			p.pending[id] = &pendingMatch{game: g}
+		}
+	}
+
+	p.games = current
+}
+
+// fetchMatches looks up the matches of ended games in match history.
+func (p *FeaturedGamesPoller) fetchMatches(ctx context.Context, events chan<- FeaturedGameEvent) {
+	for id, pm := range p.pending {
+		pm.attempts++
+		m, resp, err := p.client.Match.MatchByID(id)
+		if err == nil && resp != nil && resp.StatusCode == http.StatusOK {
+			delete(p.pending, id)
+			g := pm.game
+			sendFeaturedGameEvent(ctx, events, FeaturedGameEvent{Type: MatchAvailable, GameID: id, Game: &g, Match: m})
+			continue
+		}
+
+		// A 404 means the match has not reached match history yet.
+		if resp == nil || resp.StatusCode != http.StatusNotFound {
+			err = checkResponse(resp, err)
+			sendFeaturedGameEvent(ctx, events, FeaturedGameEvent{Type: PollError, GameID: id, Err: err})
+		}
+		if pm.attempts >= p.options.MatchAttempts {
+			delete(p.pending, id)
+		}
+	}
+}
+
+func sendFeaturedGameEvent(ctx context.Context, events chan<- FeaturedGameEvent, e FeaturedGameEvent) {
+	select {
+	case events <- e:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,85 @@
+package ionia
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFeaturedGamesPoller(t *testing.T) {
+	client, mux, _, teardown := createTestServer()
+	defer teardown()
+
+	lists := []string{
+		`{"gameList": [{"gameId": 1}, {"gameId": 2}]}`,
+		`{"gameList": [{"gameId": 2}, {"gameId": 3}]}`,
+	}
+	var mu sync.Mutex
+	refreshes := 0
+	mux.HandleFunc("/lol/spectator/v3/featured-games", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if refreshes < len(lists)-1 {
+			fmt.Fprint(w, lists[refreshes])
+			refreshes++
+			return
+		}
+		fmt.Fprint(w, lists[len(lists)-1])
+	})
+
+	matchRequests := 0
+	mux.HandleFunc("/lol/match/v3/matches/1", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		matchRequests++
+		if matchRequests < 2 {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"gameId": 1}`)
+	})
+
+	p := NewFeaturedGamesPoller(client, func(o *FeaturedGamesPollerOptions) {
+		o.DefaultInterval = time.Millisecond
+		o.FetchMatches = true
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events := make(chan FeaturedGameEvent)
+	done := make(chan error)
+	go func() { done <- p.Run(ctx, events) }()
+
+	want := []struct {
+		typ    FeaturedGameEventType
+		gameID int64
+	}{
+		{GameStarted, 1},
+		{GameStarted, 2},
+		{GameStarted, 3},
+		{GameEnded, 1},
+		{MatchAvailable, 1},
+	}
+	for _, w := range want {
+		select {
+		case e := <-events:
+			if e.Type != w.typ || e.GameID != w.gameID {
+				t.Fatalf("unexpected event: got %v %d, want %v %d", e.Type, e.GameID, w.typ, w.gameID)
+			}
+			if e.Type == MatchAvailable && (e.Match == nil || e.Match.GameID != 1) {
+				t.Errorf("unexpected match: %+v", e.Match)
+			}
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for %v %d", w.typ, w.gameID)
+		}
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run returned %v, want %v", err, context.Canceled)
+	}
+}
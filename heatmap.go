@@ -0,0 +1,278 @@
+package ionia
+
+import (
+	"encoding/csv"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// Map IDs as returned by the Match and Spectator APIs.
+const (
+	MapIDSummonersRift = 11
+	MapIDHowlingAbyss  = 12
+)
+
+// MapBounds describes the coordinate space of a map.
+// Positions reported in match timelines fall within these bounds.
+type MapBounds struct {
+	MinX, MinY int
+	MaxX, MaxY int
+}
+
+// Coordinate bounds for the maps documented by Riot.
+// https://developer.riotgames.com/game-constants.html
+var (
+	SummonersRiftBounds = MapBounds{MinX: -120, MinY: -120, MaxX: 14870, MaxY: 14980}
+	HowlingAbyssBounds  = MapBounds{MinX: -28, MinY: -19, MaxX: 12849, MaxY: 12858}
+)
+
+// MapBoundsByID returns the coordinate bounds for the given map ID.
+// The second return value is false if the map is not known.
+func MapBoundsByID(mapID int) (MapBounds, bool) {
+	switch mapID {
+	case MapIDSummonersRift:
+		return SummonersRiftBounds, true
+	case MapIDHowlingAbyss:
+		return HowlingAbyssBounds, true
+	}
+	return MapBounds{}, false
+}
+
+// Heatmap buckets map positions into a grid of Cols x Rows cells.
+//
+// Row 0 is the top of the map (the highest Y coordinate), so that the grid
+// reads the same way as the in-game minimap when exported.
+type Heatmap struct {
+	Bounds MapBounds
+	Cols   int
+	Rows   int
+
+	cells []int
+}
+
+// NewHeatmap creates an empty heatmap covering the given bounds.
+func NewHeatmap(bounds MapBounds, cols, rows int) *Heatmap {
+	if cols < 1 {
+		cols = 1
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	return &Heatmap{
+		Bounds: bounds,
+		Cols:   cols,
+		Rows:   rows,
+		cells:  make([]int, cols*rows),
+	}
+}
+
+// Cell returns the grid cell containing the given position.
+// The last return value is false if the position is outside the heatmap bounds.
+func (h *Heatmap) Cell(p MatchPositionDTO) (col, row int, ok bool) {
+	b := h.Bounds
+	if p.X < b.MinX || p.X > b.MaxX || p.Y < b.MinY || p.Y > b.MaxY {
+		return 0, 0, false
+	}
+
+	col = (p.X - b.MinX) * h.Cols / (b.MaxX - b.MinX + 1)
+	row = (b.MaxY - p.Y) * h.Rows / (b.MaxY - b.MinY + 1)
+	return col, row, true
+}
+
+// Add counts a single position. Positions outside the bounds are ignored.
+func (h *Heatmap) Add(p MatchPositionDTO) {
+	if col, row, ok := h.Cell(p); ok {
+		h.cells[row*h.Cols+col]++
+	}
+}
+
+// AddPath counts every position along a path.
+func (h *Heatmap) AddPath(path []PathPoint) {
+	for _, pp := range path {
+		h.Add(pp.Position)
+	}
+}
+
+// AddEvents counts the position of every event in the timeline accepted by filter.
+// A nil filter accepts all events. Only kills carry a position in match timelines,
+// so other events, such as WARD_PLACED or ITEM_PURCHASED, are never counted.
+func (h *Heatmap) AddEvents(mt *MatchTimelineDTO, filter EventFilter) {
+	for _, f := range mt.Frames {
+		for i := range f.Events {
+			e := &f.Events[i]
+			if positionedEvents[e.Type] && (filter == nil || filter(e)) {
+				h.Add(e.Position)
+			}
+		}
+	}
+}
+
+// positionedEvents are the timeline event types which have a position. The
+// position of other events decodes as (0, 0), which is inside the map bounds.
+var positionedEvents = map[string]bool{
+	"CHAMPION_KILL":      true,
+	"BUILDING_KILL":      true,
+	"ELITE_MONSTER_KILL": true,
+}
+
+// Count returns the number of positions counted in the given cell.
+func (h *Heatmap) Count(col, row int) int {
+	if col < 0 || col >= h.Cols || row < 0 || row >= h.Rows {
+		return 0
+	}
+	return h.cells[row*h.Cols+col]
+}
+
+// Max returns the highest count of any cell.
+func (h *Heatmap) Max() int {
+	max := 0
+	for _, c := range h.cells {
+		if c > max {
+			max = c
+		}
+	}
+	return max
+}
+
+// WriteCSV writes the heatmap as CSV, one row of the grid per line.
+func (h *Heatmap) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	record := make([]string, h.Cols)
+	for row := 0; row < h.Rows; row++ {
+		for col := 0; col < h.Cols; col++ {
+			record[col] = strconv.Itoa(h.Count(col, row))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WritePNG renders the heatmap as a PNG image, drawing each cell as a
+// cellSize x cellSize square. Empty cells are transparent, and busier
+// cells range from red to yellow.
+func (h *Heatmap) WritePNG(w io.Writer, cellSize int) error {
+	if cellSize < 1 {
+		cellSize = 1
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, h.Cols*cellSize, h.Rows*cellSize))
+	max := h.Max()
+	for row := 0; row < h.Rows; row++ {
+		for col := 0; col < h.Cols; col++ {
+			c := heatColor(h.Count(col, row), max)
+			for y := row * cellSize; y < (row+1)*cellSize; y++ {
+				for x := col * cellSize; x < (col+1)*cellSize; x++ {
+					img.SetNRGBA(x, y, c)
+				}
+			}
+		}
+	}
+
+	return png.Encode(w, img)
+}
+
+func heatColor(count, max int) color.NRGBA {
+	if count == 0 || max == 0 {
+		return color.NRGBA{}
+	}
+	t := float64(count) / float64(max)
+	return color.NRGBA{
+		R: 255,
+		G: uint8(255 * t),
+		B: 0,
+		A: uint8(96 + 159*t),
+	}
+}
+
+// EventFilter reports whether a timeline event should be counted.
+type EventFilter func(e *MatchEventDTO) bool
+
+// KillFilter accepts CHAMPION_KILL events where one of the given participants
+// was the killer. If no participant IDs are given, all kills are accepted.
+func KillFilter(participantIDs ...int) EventFilter {
+	return func(e *MatchEventDTO) bool {
+		return e.Type == "CHAMPION_KILL" && containsID(participantIDs, e.KillerID)
+	}
+}
+
+// DeathFilter accepts CHAMPION_KILL events where one of the given participants
+// was the victim. If no participant IDs are given, all deaths are accepted.
+func DeathFilter(participantIDs ...int) EventFilter {
+	return func(e *MatchEventDTO) bool {
+		return e.Type == "CHAMPION_KILL" && containsID(participantIDs, e.VictimID)
+	}
+}
+
+func containsID(ids []int, id int) bool {
+	if len(ids) == 0 {
+		return true
+	}
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+// PathPoint is the position of a participant at a point in time.
+type PathPoint struct {
+	Timestamp int64
+	Position  MatchPositionDTO
+}
+
+// ParticipantPaths returns the position of each participant at every frame
+// of the timeline, keyed by participant ID.
+func ParticipantPaths(mt *MatchTimelineDTO) map[int][]PathPoint {
+	paths := make(map[int][]PathPoint)
+	for _, f := range mt.Frames {
+		for id, pf := range f.ParticipantFrames {
+			if pf.ParticipantID != 0 {
+				id = pf.ParticipantID
+			}
+			paths[id] = append(paths[id], PathPoint{
+				Timestamp: f.Timestamp,
+				Position:  pf.Position,
+			})
+		}
+	}
+	return paths
+}
+
+// WritePathsCSV writes participant paths as CSV with the columns
+// participantId, timestamp, x and y, ordered by participant ID.
+func WritePathsCSV(w io.Writer, paths map[int][]PathPoint) error {
+	ids := make([]int, 0, len(paths))
+	for id := range paths {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"participantId", "timestamp", "x", "y"}); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		for _, pp := range paths[id] {
+			err := cw.Write([]string{
+				strconv.Itoa(id),
+				strconv.FormatInt(pp.Timestamp, 10),
+				strconv.Itoa(pp.Position.X),
+				strconv.Itoa(pp.Position.Y),
+			})
+			if err != nil {
+				return fmt.Errorf("writing path for participant %d: %v", id, err)
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
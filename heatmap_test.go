@@ -0,0 +1,143 @@
+package ionia
+
+import (
+	"bytes"
+	"image/png"
+	"reflect"
+	"testing"
+)
+
+var heatmapTimeline = &MatchTimelineDTO{
+	Frames: []MatchFrameDTO{
+		{
+			Timestamp: 0,
+			ParticipantFrames: map[int]MatchParticipantFrameDTO{
+				1: {ParticipantID: 1, Position: MatchPositionDTO{X: 0, Y: 0}},
+				2: {ParticipantID: 2, Position: MatchPositionDTO{X: 14000, Y: 14000}},
+			},
+		},
+		{
+			Timestamp: 60000,
+			ParticipantFrames: map[int]MatchParticipantFrameDTO{
+				1: {ParticipantID: 1, Position: MatchPositionDTO{X: 100, Y: 14500}},
+				2: {ParticipantID: 2, Position: MatchPositionDTO{X: 14000, Y: 100}},
+			},
+			Events: []MatchEventDTO{
+				{Type: "CHAMPION_KILL", KillerID: 1, VictimID: 2, Position: MatchPositionDTO{X: 100, Y: 100}},
+				// Like most events, wards and purchases have no position.
+				{Type: "WARD_PLACED", CreatorID: 2, WardType: "YELLOW_TRINKET"},
+				{Type: "ITEM_PURCHASED", ParticipantID: 1, ItemID: 1055},
+				{Type: "BUILDING_KILL", KillerID: 1, Position: MatchPositionDTO{X: 13866, Y: 4505}},
+				{Type: "CHAMPION_KILL", KillerID: 2, VictimID: 1, Position: MatchPositionDTO{X: 14000, Y: 100}},
+			},
+		},
+	},
+}
+
+func TestHeatmapCell(t *testing.T) {
+	h := NewHeatmap(SummonersRiftBounds, 2, 2)
+
+	tt := []struct {
+		name     string
+		pos      MatchPositionDTO
+		col, row int
+		ok       bool
+	}{
+		{name: "Bottom Left", pos: MatchPositionDTO{X: -120, Y: -120}, col: 0, row: 1, ok: true},
+		{name: "Top Right", pos: MatchPositionDTO{X: 14870, Y: 14980}, col: 1, row: 0, ok: true},
+		{name: "Out Of Bounds", pos: MatchPositionDTO{X: 20000, Y: 0}, ok: false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			col, row, ok := h.Cell(tc.pos)
+			if ok != tc.ok || (ok && (col != tc.col || row != tc.row)) {
+				t.Errorf("Cell(%v) = %d, %d, %v, want %d, %d, %v", tc.pos, col, row, ok, tc.col, tc.row, tc.ok)
+			}
+		})
+	}
+}
+
+func TestHeatmapAddEvents(t *testing.T) {
+	tt := []struct {
+		name   string
+		filter EventFilter
+		want   []int
+	}{
+		{name: "All Kills", filter: KillFilter(), want: []int{0, 0, 1, 1}},
+		{name: "Kills By Participant", filter: KillFilter(1), want: []int{0, 0, 1, 0}},
+		{name: "Deaths Of Participant", filter: DeathFilter(1), want: []int{0, 0, 0, 1}},
+		{name: "All Events", want: []int{0, 0, 1, 2}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHeatmap(SummonersRiftBounds, 2, 2)
+			h.AddEvents(heatmapTimeline, tc.filter)
+			got := []int{h.Count(0, 0), h.Count(1, 0), h.Count(0, 1), h.Count(1, 1)}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("unexpected cell counts: got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHeatmapAddEventsWithoutPosition(t *testing.T) {
+	h := NewHeatmap(SummonersRiftBounds, 100, 100)
+	h.AddEvents(heatmapTimeline, nil)
+	col, row, _ := h.Cell(MatchPositionDTO{})
+	if n := h.Count(col, row); n != 0 {
+		t.Errorf("expected no events at (0, 0), got %d", n)
+	}
+}
+
+func TestParticipantPaths(t *testing.T) {
+	got := ParticipantPaths(heatmapTimeline)
+	want := map[int][]PathPoint{
+		1: {
+			{Timestamp: 0, Position: MatchPositionDTO{X: 0, Y: 0}},
+			{Timestamp: 60000, Position: MatchPositionDTO{X: 100, Y: 14500}},
+		},
+		2: {
+			{Timestamp: 0, Position: MatchPositionDTO{X: 14000, Y: 14000}},
+			{Timestamp: 60000, Position: MatchPositionDTO{X: 14000, Y: 100}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParticipantPaths = %+v, want %+v", got, want)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := WritePathsCSV(buf, got); err != nil {
+		t.Fatalf("WritePathsCSV returned error: %v", err)
+	}
+	wantCSV := "participantId,timestamp,x,y\n1,0,0,0\n1,60000,100,14500\n2,0,14000,14000\n2,60000,14000,100\n"
+	if buf.String() != wantCSV {
+		t.Errorf("WritePathsCSV wrote %q, want %q", buf.String(), wantCSV)
+	}
+}
+
+func TestHeatmapExport(t *testing.T) {
+	h := NewHeatmap(SummonersRiftBounds, 2, 2)
+	h.AddPath(ParticipantPaths(heatmapTimeline)[1])
+
+	buf := &bytes.Buffer{}
+	if err := h.WriteCSV(buf); err != nil {
+		t.Fatalf("WriteCSV returned error: %v", err)
+	}
+	if want := "1,0\n1,0\n"; buf.String() != want {
+		t.Errorf("WriteCSV wrote %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := h.WritePNG(buf, 4); err != nil {
+		t.Fatalf("WritePNG returned error: %v", err)
+	}
+	img, err := png.Decode(buf)
+	if err != nil {
+		t.Fatalf("png.Decode returned error: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 8 || b.Dy() != 8 {
+		t.Errorf("unexpected image size: got %dx%d, want 8x8", b.Dx(), b.Dy())
+	}
+}
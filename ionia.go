@@ -12,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/go-querystring/query"
 )
@@ -42,9 +43,35 @@ type Client struct {
 
 	rateMu     sync.Mutex
 	rateLimits map[string]Rate
+	rateResets map[string]map[int]time.Time
+	rateSubs   []*rateSubscription
+	rateStore  RateLimitStore
 
-	// Riot API Key.
-	apiKey string
+	scheduler    *Scheduler
+	priority     Priority
+	tenant       string
+	tenantWeight int
+	pacing       *PacingOptions
+	retry        *RetryOptions
+	breaker      *Breaker
+
+	coalescing bool
+	keepRaw    bool
+
+	unknownFields func(req *http.Request, fields []string, extras map[string]json.RawMessage)
+	flightMu      sync.Mutex
+	flights       map[string]*flight
+
+	middleware []Middleware
+	logger     Logger
+	metrics    *Metrics
+
+	// Static data names fetched by SpectatorService.EnrichCurrentGame.
+	staticNames staticNameCache
+
+	// Riot API Keys.
+	keyMu sync.Mutex
+	keys  []apiKey
 
 	// API Sections.
 	ChampionMastery *ChampionMasteryService
@@ -74,6 +101,23 @@ func WithRegion(region string) ClientOption {
 	}
 }
 
+// WithHTTPClient returns a ClientOption which sets the http.Client used to send requests.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) {
+		c.client = hc
+	}
+}
+
+// WithTransport returns a ClientOption which sends requests through the given RoundTripper.
+// Other settings of the Client's http.Client, such as its timeout, are kept.
+func WithTransport(rt http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		hc := *c.client
+		hc.Transport = rt
+		c.client = &hc
+	}
+}
+
 // NewClient creates a new Riot API client.
 // Any number of ClientOptions can be passed, and will
 // be applied after the default client has been created.
@@ -81,10 +125,14 @@ func NewClient(riotToken string, opts ...ClientOption) *Client {
 	baseURL, _ := url.Parse(fmt.Sprintf(defaultBaseURL, defaultRegion))
 
 	c := &Client{
-		apiKey:     riotToken,
+		keys:       newAPIKeys([]string{riotToken}),
 		client:     http.DefaultClient,
 		BaseURL:    baseURL,
 		rateLimits: make(map[string]Rate),
+		rateResets: make(map[string]map[int]time.Time),
+		rateStore:  NewMemoryRateLimitStore(),
+
+		tenantWeight: 1,
 	}
 	c.common.client = c
 	c.ChampionMastery = (*ChampionMasteryService)(&c.common)
@@ -102,6 +150,12 @@ func NewClient(riotToken string, opts ...ClientOption) *Client {
 	for _, opt := range opts {
 		opt(c)
 	}
+	if c.pacing != nil {
+		c.rateStore = newPacedStore(c.rateStore, c.pacing)
+		if c.scheduler == nil {
+			c.scheduler = NewScheduler()
+		}
+	}
 
 	return c
 }
@@ -138,7 +192,7 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
-	req.Header.Set(headerRiotToken, c.apiKey)
+	req.Header.Set(headerRiotToken, c.apiKeys()[0].key)
 
 	return req, nil
 }
@@ -147,37 +201,163 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 // decoded and stored in the value pointed to by v, or returned as an error
 // if an API error has occurred.
 func (c *Client) Do(req *http.Request, v interface{}) (*http.Response, error) {
-	rateMethod := getMethod(req.URL.Path)
-	if errResp := c.checkRateLimit(req, rateMethod); errResp != nil {
-		return errResp, nil
-	}
+	return c.doDecode(req, false, func(r io.Reader) error {
+		if v == nil {
+			return nil
+		}
+		if c.unknownFields == nil {
+			err := json.NewDecoder(r).Decode(v)
+			if err == io.EOF {
+				err = nil // ignore EOF errors caused by empty response body
+			}
+			return err
+		}
 
-	resp, err := c.client.Do(req)
+		b, err := ioutil.ReadAll(r)
+		if err != nil || len(b) == 0 {
+			return err
+		}
+		if err := json.Unmarshal(b, v); err != nil {
+			return err
+		}
+		if fields, _ := UnknownFields(b, v); len(fields) > 0 {
+			extras, _ := Extras(b, v)
+			c.unknownFields(req, fields, extras)
+		}
+		return nil
+	})
+}
+
+// doDecode sends an API request like Do, passing the body of a 200 OK response
+// to decode. Unless streaming is true, the body is kept in the returned response
+// if the Client keeps raw responses, and identical requests may be coalesced;
+// both would read the whole body into memory.
+func (c *Client) doDecode(req *http.Request, streaming bool, decode func(io.Reader) error) (*http.Response, error) {
+	rateMethod := getMethod(req.Method, req.URL.Path)
+	var resp *http.Response
+	var denied bool
+	var err error
+	if c.coalescing && !streaming && coalescable(req) {
+		resp, denied, err = c.coalesce(req, rateMethod)
+	} else {
+		resp, denied, err = c.do(req, rateMethod)
+	}
+	if denied {
+		return resp, err
+	}
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	// Parse rate limit information.
-	appRate, methodRate := parseRates(resp)
-	c.rateMu.Lock()
-	c.rateLimits["app"] = *appRate
-	c.rateLimits[rateMethod] = *methodRate
-	c.rateMu.Unlock()
-
 	// All valid Riot API responses should return 200 OK.
 	if resp.StatusCode != http.StatusOK {
 		return resp, fmt.Errorf("api returned error: %s %d", resp.Status, resp.StatusCode)
 	}
 
-	if v != nil {
-		err = json.NewDecoder(resp.Body).Decode(v)
-		if err == io.EOF {
-			err = nil // ignore EOF errors caused by empty response body
+	if streaming || !c.keepRaw {
+		return resp, decode(resp.Body)
+	}
+	raw, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return resp, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(raw))
+	return resp, decode(bytes.NewReader(raw))
+}
+
+// do sends req, retrying it according to the Client's retry policy. The
+// response body is left open.
+func (c *Client) do(req *http.Request, rateMethod string) (resp *http.Response, denied bool, err error) {
+	for attempt := 1; ; attempt++ {
+		resp, denied, err = c.send(req, rateMethod)
+		if denied {
+			return resp, true, err
+		}
+		delay, retry := c.retry.retryDelay(req, attempt, resp, err)
+		if !retry {
+			break
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		c.logRetry(req, rateMethod, attempt, delay, resp, err)
+		if c.metrics != nil {
+			c.metrics.observeRetry(req, rateMethod)
+		}
+		if err := sleepRetry(req, delay); err != nil {
+			return nil, false, err
 		}
 	}
+	return resp, false, err
+}
 
-	return resp, err
+// send makes a single attempt at sending req, once the rate limits allow it.
+// If the request is not sent, denied is true, along with a synthetic response
+// if the rate limits denied it, or an error such as a CircuitOpenError.
+func (c *Client) send(req *http.Request, rateMethod string) (resp *http.Response, denied bool, err error) {
+	platform := platformOf(req.URL)
+	if c.breaker != nil {
+		done, err := c.breaker.allow(platform, rateMethod)
+		if err != nil {
+			return nil, true, err
+		}
+		defer func() {
+			if denied {
+				done(nil, nil)
+			} else {
+				done(resp, err)
+			}
+		}()
+	}
+
+	key := c.pickKey(platform, rateMethod)
+	req.Header.Set(headerRiotToken, key.key)
+	keys := []string{limitKey(key.id, platform, appRateMethod), limitKey(key.id, platform, rateMethod)}
+	if c.scheduler != nil {
+		p, tenant, weight := c.schedule(req)
+		if err := c.scheduler.wait(req, c.rateStore, keys, p, tenant, weight); err != nil {
+			return nil, true, err
+		}
+	} else {
+		errResp, err := c.checkRateLimit(req, keys...)
+		if err != nil {
+			return nil, true, err
+		}
+		if errResp != nil {
+			c.logDenied(req, key.id, rateMethod, errResp.Header.Get(headerRetryAfter))
+			return errResp, true, nil
+		}
+	}
+
+	call := &Call{Request: req, Method: rateMethod, Key: key.id}
+	start := time.Now()
+	resp, err = c.roundTrip(call)
+	if err != nil {
+		c.logCall(call, nil, err, time.Since(start))
+		return nil, false, err
+	}
+
+	// Parse rate limit information, unless a middleware already did.
+	if call.AppRate == nil || call.MethodRate == nil {
+		call.AppRate, call.MethodRate = parseRates(resp)
+	}
+	c.logCall(call, resp, nil, time.Since(start))
+	c.updateRates(key.id, platform, rateMethod, call.AppRate, call.MethodRate)
+	return resp, false, nil
+}
+
+// checkResponse returns err, or an error describing resp if it is not 200 OK.
+// Requests denied by checkRateLimit return a synthetic response and no error,
+// so callers which need a usable result must check the status as well.
+func checkResponse(resp *http.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	if resp != nil && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("api returned error: %s %d", resp.Status, resp.StatusCode)
+	}
+	return nil
 }
 
 // addOptions adds the parameters in opt as URL query parameters to s.
@@ -202,47 +382,144 @@ func addOptions(s string, opt interface{}) (string, error) {
 	return u.String(), nil
 }
 
-func (c *Client) checkRateLimit(req *http.Request, method string) *http.Response {
-	c.rateMu.Lock()
-	rate := c.rateLimits[method]
-	c.rateMu.Unlock()
-
-	for s, count := range rate.Counts {
-		if r, ok := rate.Limits[s]; ok {
-			if count.Used >= r.Allowed {
-				// Create a fake response.
-				return &http.Response{
-					Status:     http.StatusText(http.StatusForbidden),
-					StatusCode: http.StatusForbidden,
-					Request:    req,
-					Header:     make(http.Header),
-					Body:       ioutil.NopCloser(strings.NewReader("")),
-				}
-			}
-		}
+// checkRateLimit takes a request from the rate limit store for each key. If any of
+// their windows is full, a synthetic 403 response is returned, with a Retry-After
+// header giving the number of seconds until the window resets.
+func (c *Client) checkRateLimit(req *http.Request, keys ...string) (*http.Response, error) {
+	wait, err := c.rateStore.Take(keys, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("rate limit store: %v", err)
+	}
+	if wait <= 0 {
+		return nil, nil
 	}
 
-	return nil
+	// Create a fake response.
+	header := make(http.Header)
+	header.Set(headerRetryAfter, strconv.Itoa(int((wait+time.Second-1)/time.Second)))
+	return &http.Response{
+		Status:     http.StatusText(http.StatusForbidden),
+		StatusCode: http.StatusForbidden,
+		Request:    req,
+		Header:     header,
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+	}, nil
 }
 
-// Looks up the method name of the given path.
+// Looks up the method name of the given request method and path.
 // These names are based on the ones given on the Rate Limits page
 // of the Riot API Documentation (https://developer.riotgames.com/rate-limiting.html).
 //
 // The format is the HTTP Request Method followed by a few words to describe the method.
 // (e.g. /lol/platform/v3/champions => GET_getAllChampions).
-func getMethod(path string) string {
-	switch {
-	case strings.HasPrefix(path, "/lol/platform/v3/champions"):
-		if strings.HasSuffix(path, "/lol/platform/v3/champions") {
-			return "GET_getAllChampions"
+// Paths which are not part of the API are returned as is.
+func getMethod(method, path string) string {
+	segments := strings.Split(path, "/")
+	for _, m := range methodNames {
+		if m.method == method && matchSegments(m.pattern, segments) {
+			return m.name
 		}
-		return "GET_getChampionById"
 	}
 
 	return path
 }
 
+// matchSegments reports whether the trailing path segments match the pattern,
+// in which "*" matches any non-empty segment. Only the trailing segments are
+// compared so that a BaseURL with a path prefix does not affect the match.
+func matchSegments(pattern, segments []string) bool {
+	if len(segments) < len(pattern) {
+		return false
+	}
+	segments = segments[len(segments)-len(pattern):]
+	for i, p := range pattern {
+		if p == "*" && segments[i] != "" {
+			continue
+		}
+		if p != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+type methodName struct {
+	method  string
+	pattern []string
+	name    string
+}
+
+func newMethodName(method, pattern, name string) methodName {
+	return methodName{method, strings.Split(pattern, "/"), name}
+}
+
+// methodNames lists the method name of every endpoint implemented by the client.
+// Where one pattern is a suffix of another, the longer pattern comes first.
+var methodNames = []methodName{
+	newMethodName(http.MethodGet, "lol/platform/v3/champions", "GET_getAllChampions"),
+	newMethodName(http.MethodGet, "lol/platform/v3/champions/*", "GET_getChampionById"),
+	newMethodName(http.MethodGet, "lol/platform/v3/third-party-code/by-summoner/*", "GET_getThirdPartyCodeBySummonerId"),
+
+	newMethodName(http.MethodGet, "lol/champion-mastery/v3/champion-masteries/by-summoner/*", "GET_getAllChampionMasteries"),
+	newMethodName(http.MethodGet, "lol/champion-mastery/v3/champion-masteries/by-summoner/*/by-champion/*", "GET_getChampionMastery"),
+	newMethodName(http.MethodGet, "lol/champion-mastery/v3/scores/by-summoner/*", "GET_getChampionMasteryScore"),
+
+	newMethodName(http.MethodGet, "lol/league/v3/challengerleagues/by-queue/*", "GET_getChallengerLeague"),
+	newMethodName(http.MethodGet, "lol/league/v3/leagues/*", "GET_getLeagueById"),
+	newMethodName(http.MethodGet, "lol/league/v3/masterleagues/by-queue/*", "GET_getMasterLeague"),
+	newMethodName(http.MethodGet, "lol/league/v3/positions/by-summoner/*", "GET_getAllLeaguePositionsForSummoner"),
+
+	newMethodName(http.MethodGet, "lol/static-data/v3/champions", "GET_getChampionList"),
+	newMethodName(http.MethodGet, "lol/static-data/v3/champions/*", "GET_getStaticChampionById"),
+	newMethodName(http.MethodGet, "lol/static-data/v3/items", "GET_getItemList"),
+	newMethodName(http.MethodGet, "lol/static-data/v3/items/*", "GET_getItemById"),
+	newMethodName(http.MethodGet, "lol/static-data/v3/language-strings", "GET_getLanguageStrings"),
+	newMethodName(http.MethodGet, "lol/static-data/v3/languages", "GET_getLanguages"),
+	newMethodName(http.MethodGet, "lol/static-data/v3/maps", "GET_getMapData"),
+	newMethodName(http.MethodGet, "lol/static-data/v3/masteries", "GET_getMasteryList"),
+	newMethodName(http.MethodGet, "lol/static-data/v3/masteries/*", "GET_getMasteryById"),
+	newMethodName(http.MethodGet, "lol/static-data/v3/profile-icons", "GET_getProfileIcons"),
+	newMethodName(http.MethodGet, "lol/static-data/v3/realms", "GET_getRealm"),
+	newMethodName(http.MethodGet, "lol/static-data/v3/reforged-rune-paths", "GET_getReforgedRunePaths"),
+	newMethodName(http.MethodGet, "lol/static-data/v3/reforged-rune-paths/*", "GET_getReforgedRunePathById"),
+	newMethodName(http.MethodGet, "lol/static-data/v3/reforged-runes", "GET_getReforgedRunes"),
+	newMethodName(http.MethodGet, "lol/static-data/v3/reforged-runes/*", "GET_getReforgedRuneById"),
+	newMethodName(http.MethodGet, "lol/static-data/v3/runes", "GET_getRuneList"),
+	newMethodName(http.MethodGet, "lol/static-data/v3/runes/*", "GET_getRuneById"),
+	newMethodName(http.MethodGet, "lol/static-data/v3/summoner-spells", "GET_getSummonerSpellList"),
+	newMethodName(http.MethodGet, "lol/static-data/v3/summoner-spells/*", "GET_getSummonerSpellById"),
+	newMethodName(http.MethodGet, "lol/static-data/v3/tarball-links", "GET_getTarballLinks"),
+	newMethodName(http.MethodGet, "lol/static-data/v3/versions", "GET_getVersions"),
+
+	newMethodName(http.MethodGet, "lol/status/v3/shard-data", "GET_getShardData"),
+
+	newMethodName(http.MethodGet, "lol/match/v3/matches/by-tournament-code/*/ids", "GET_getMatchIdsByTournamentCode"),
+	newMethodName(http.MethodGet, "lol/match/v3/matches/*/by-tournament-code/*", "GET_getMatchByTournamentCode"),
+	newMethodName(http.MethodGet, "lol/match/v3/matches/*", "GET_getMatch"),
+	newMethodName(http.MethodGet, "lol/match/v3/matchlists/by-account/*/recent/", "GET_getRecentMatchlist"),
+	newMethodName(http.MethodGet, "lol/match/v3/matchlists/by-account/*", "GET_getMatchlist"),
+	newMethodName(http.MethodGet, "lol/match/v3/timelines/by-match/*", "GET_getMatchTimeline"),
+
+	newMethodName(http.MethodGet, "lol/spectator/v3/active-games/by-summoner/*", "GET_getCurrentGameInfoBySummoner"),
+	newMethodName(http.MethodGet, "lol/spectator/v3/featured-games", "GET_getFeaturedGames"),
+
+	newMethodName(http.MethodGet, "lol/summoner/v3/summoners/by-account/*", "GET_getByAccountId"),
+	newMethodName(http.MethodGet, "lol/summoner/v3/summoners/by-name/*", "GET_getBySummonerName"),
+	newMethodName(http.MethodGet, "lol/summoner/v3/summoners/*", "GET_getBySummonerId"),
+
+	newMethodName(http.MethodPost, "lol/tournament-stub/v3/codes", "POST_createTournamentCode"),
+	newMethodName(http.MethodGet, "lol/tournament-stub/v3/lobby-events/by-code/*", "GET_getLobbyEventsByCode"),
+	newMethodName(http.MethodPost, "lol/tournament-stub/v3/providers", "POST_registerProviderData"),
+	newMethodName(http.MethodPost, "lol/tournament-stub/v3/tournaments", "POST_registerTournament"),
+
+	newMethodName(http.MethodPost, "lol/tournament/v3/codes", "POST_createTournamentCode"),
+	newMethodName(http.MethodPut, "lol/tournament/v3/codes/*", "PUT_updateCode"),
+	newMethodName(http.MethodGet, "lol/tournament/v3/codes/*", "GET_getTournamentCode"),
+	newMethodName(http.MethodGet, "lol/tournament/v3/lobby-events/by-code/*", "GET_getLobbyEventsByCode"),
+	newMethodName(http.MethodPost, "lol/tournament/v3/providers", "POST_registerProviderData"),
+	newMethodName(http.MethodPost, "lol/tournament/v3/tournaments", "POST_registerTournament"),
+}
+
 // Parses all of the rate limit information returned from an API request.
 //
 // Each request will contain information about the application limits,
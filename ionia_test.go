@@ -8,6 +8,7 @@ import (
 	"os"
 	"reflect"
 	"testing"
+	"time"
 )
 
 const (
@@ -50,6 +51,39 @@ func TestWithRegion(t *testing.T) {
 	}
 }
 
+func TestWithHTTPClient(t *testing.T) {
+	hc := &http.Client{}
+	client := NewClient("", WithHTTPClient(hc))
+
+	if client.client != hc {
+		t.Errorf("expected client to use the given http.Client")
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestWithTransport(t *testing.T) {
+	var called bool
+	rt := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		called = true
+		return &http.Response{StatusCode: http.StatusOK, Header: make(http.Header), Body: http.NoBody, Request: r}, nil
+	})
+	client := NewClient("", WithTransport(rt))
+
+	req, _ := client.NewRequest(http.MethodGet, "lol/platform/v3/champions", nil)
+	if _, err := client.Do(req, nil); err != nil {
+		t.Fatalf("Do returned unexpected error: %v", err)
+	}
+	if !called {
+		t.Error("expected request to be sent through the transport")
+	}
+	if http.DefaultClient.Transport != nil {
+		t.Error("WithTransport modified http.DefaultClient")
+	}
+}
+
 func TestNewRequest(t *testing.T) {
 	apiKey := "testing"
 	c := NewClient(apiKey)
@@ -216,24 +250,69 @@ func TestParseRateString(t *testing.T) {
 func TestGetMethod(t *testing.T) {
 	tt := []struct {
 		name     string
+		method   string
 		path     string
 		expected string
 	}{
 		{
 			name:     "Get All Champions",
+			method:   http.MethodGet,
 			path:     "/lol/platform/v3/champions",
 			expected: "GET_getAllChampions",
 		},
 		{
 			name:     "Get Champion By ID",
+			method:   http.MethodGet,
 			path:     "/lol/platform/v3/champions/123",
 			expected: "GET_getChampionById",
 		},
+		{
+			name:     "Base Path Prefix",
+			method:   http.MethodGet,
+			path:     "/test/lol/platform/v3/champions",
+			expected: "GET_getAllChampions",
+		},
+		{
+			name:     "Get Summoner By Name",
+			method:   http.MethodGet,
+			path:     "/lol/summoner/v3/summoners/by-name/Doublelift",
+			expected: "GET_getBySummonerName",
+		},
+		{
+			name:     "Get Summoner By ID",
+			method:   http.MethodGet,
+			path:     "/lol/summoner/v3/summoners/42",
+			expected: "GET_getBySummonerId",
+		},
+		{
+			name:     "Get Match IDs By Tournament Code",
+			method:   http.MethodGet,
+			path:     "/lol/match/v3/matches/by-tournament-code/CODE/ids",
+			expected: "GET_getMatchIdsByTournamentCode",
+		},
+		{
+			name:     "Get Recent Matchlist",
+			method:   http.MethodGet,
+			path:     "/lol/match/v3/matchlists/by-account/1/recent/",
+			expected: "GET_getRecentMatchlist",
+		},
+		{
+			name:     "Update Tournament Code",
+			method:   http.MethodPut,
+			path:     "/lol/tournament/v3/codes/CODE",
+			expected: "PUT_updateCode",
+		},
+		{
+			name:     "Unknown Path",
+			method:   http.MethodGet,
+			path:     "/lol/unknown/v1/things",
+			expected: "/lol/unknown/v1/things",
+		},
 	}
 
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
-			if m := getMethod(tc.path); m != tc.expected {
+			if m := getMethod(tc.method, tc.path); m != tc.expected {
 				t.Errorf("unexpected method returned: got %s, want %s", m, tc.expected)
 			}
 		})
@@ -243,29 +322,35 @@ func TestGetMethod(t *testing.T) {
 func TestCheckRateLimit(t *testing.T) {
 	c := NewClient("")
 
-	c.rateLimits = map[string]Rate{
-		"okay": {
-			Counts: map[int]Count{
-				1: {9, 1},
-			},
-			Limits: map[int]Limit{
-				1: {10, 1},
-			},
+	now := time.Now()
+	c.rateStore.Update("okay", Rate{
+		Counts: map[int]Count{
+			1: {9, 1},
 		},
-		"error": {
-			Counts: map[int]Count{
-				1: {10, 1},
-			},
-			Limits: map[int]Limit{
-				1: {10, 1},
-			},
+		Limits: map[int]Limit{
+			1: {10, 1},
 		},
-	}
+	}, now)
+	c.rateStore.Update("error", Rate{
+		Counts: map[int]Count{
+			1: {10, 1},
+		},
+		Limits: map[int]Limit{
+			1: {10, 1},
+		},
+	}, now)
 
-	if resp := c.checkRateLimit(nil, "okay"); resp != nil {
-		t.Errorf("unexpected response returned: %v", resp)
+	if resp, err := c.checkRateLimit(nil, "okay"); err != nil || resp != nil {
+		t.Errorf("unexpected response returned: %v, %v", resp, err)
+	}
+	if resp, err := c.checkRateLimit(nil, "okay"); err != nil || resp == nil {
+		t.Errorf("expected response once the window is full, got: %v, %v", resp, err)
+	}
+	resp, err := c.checkRateLimit(nil, "error")
+	if err != nil || resp == nil {
+		t.Fatalf("expected response, got: %v, %v", resp, err)
 	}
-	if resp := c.checkRateLimit(nil, "error"); resp == nil {
-		t.Errorf("expected response, got: %v", resp)
+	if got := resp.Header.Get(headerRetryAfter); got != "1" {
+		t.Errorf("expected Retry-After 1, got %q", got)
 	}
 }
@@ -0,0 +1,535 @@
+package ioniatest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/brattonross/ionia"
+)
+
+// routes lists every endpoint implemented by the ionia client, along with the
+// method name used for its rate limit and the fixture served by default.
+// Fixtures are derived from the request's path parameters, so that lookups
+// of different IDs return consistent but distinct data.
+var routes = []route{
+	// Champion-V3
+	newRoute(http.MethodGet, "lol/platform/v3/champions", "GET_getAllChampions", func(r *request) interface{} {
+		return &ionia.ChampionListDTO{Champions: []ionia.ChampionDTO{champion(1), champion(17), champion(103)}}
+	}),
+	newRoute(http.MethodGet, "lol/platform/v3/champions/{id}", "GET_getChampionById", func(r *request) interface{} {
+		c := champion(r.int64Param("id"))
+		return &c
+	}),
+
+	// Champion-Mastery-V3
+	newRoute(http.MethodGet, "lol/champion-mastery/v3/champion-masteries/by-summoner/{summonerId}", "GET_getAllChampionMasteries", func(r *request) interface{} {
+		id := r.int64Param("summonerId")
+		return []ionia.ChampionMasteryDTO{mastery(id, 17), mastery(id, 1)}
+	}),
+	newRoute(http.MethodGet, "lol/champion-mastery/v3/champion-masteries/by-summoner/{summonerId}/by-champion/{championId}", "GET_getChampionMastery", func(r *request) interface{} {
+		return mastery(r.int64Param("summonerId"), r.int64Param("championId"))
+	}),
+	newRoute(http.MethodGet, "lol/champion-mastery/v3/scores/by-summoner/{summonerId}", "GET_getChampionMasteryScore", func(r *request) interface{} {
+		return 42
+	}),
+
+	// League-V3
+	newRoute(http.MethodGet, "lol/league/v3/challengerleagues/by-queue/{queue}", "GET_getChallengerLeague", func(r *request) interface{} {
+		return league("CHALLENGER", r.params["queue"])
+	}),
+	newRoute(http.MethodGet, "lol/league/v3/leagues/{leagueId}", "GET_getLeagueById", func(r *request) interface{} {
+		l := league("DIAMOND", "RANKED_SOLO_5x5")
+		l.LeagueID = r.params["leagueId"]
+		return l
+	}),
+	newRoute(http.MethodGet, "lol/league/v3/masterleagues/by-queue/{queue}", "GET_getMasterLeague", func(r *request) interface{} {
+		return league("MASTER", r.params["queue"])
+	}),
+	newRoute(http.MethodGet, "lol/league/v3/positions/by-summoner/{summonerId}", "GET_getAllLeaguePositionsForSummoner", func(r *request) interface{} {
+		id := r.params["summonerId"]
+		return []ionia.LeaguePositionDTO{{
+			Rank:             "I",
+			QueueType:        "RANKED_SOLO_5x5",
+			Wins:             120,
+			Losses:           100,
+			LeagueID:         "00000000-0000-0000-0000-000000000001",
+			PlayerOrTeamName: "Summoner " + id,
+			PlayerOrTeamID:   id,
+			LeagueName:       "Ionia's Fakers",
+			Tier:             "GOLD",
+			LeaguePoints:     75,
+		}}
+	}),
+
+	// LOL-Static-Data-V3
+	newRoute(http.MethodGet, "lol/static-data/v3/champions", "GET_getChampionList", func(r *request) interface{} {
+		data := make(map[string]ionia.StaticChampionDTO)
+		for _, id := range []int{1, 17, 103} {
+			c := staticChampion(id)
+			if r.query.Get("dataById") == "true" {
+				data[strconv.Itoa(id)] = c
+			} else {
+				data[c.Key] = c
+			}
+		}
+		return &ionia.StaticChampionListDTO{Data: data, Version: version, Type: "champion"}
+	}),
+	newRoute(http.MethodGet, "lol/static-data/v3/champions/{id}", "GET_getStaticChampionById", func(r *request) interface{} {
+		return staticChampion(int(r.int64Param("id")))
+	}),
+	newRoute(http.MethodGet, "lol/static-data/v3/items", "GET_getItemList", func(r *request) interface{} {
+		return &ionia.ItemListDTO{Data: map[string]ionia.ItemDTO{"1001": item(1001)}, Version: version, Type: "item"}
+	}),
+	newRoute(http.MethodGet, "lol/static-data/v3/items/{id}", "GET_getItemById", func(r *request) interface{} {
+		return item(int(r.int64Param("id")))
+	}),
+	newRoute(http.MethodGet, "lol/static-data/v3/language-strings", "GET_getLanguageStrings", func(r *request) interface{} {
+		return &ionia.LanguageStringsDTO{Data: map[string]string{"Back": "Back"}, Version: version, Type: "language"}
+	}),
+	newRoute(http.MethodGet, "lol/static-data/v3/languages", "GET_getLanguages", func(r *request) interface{} {
+		return []string{"en_US", "ko_KR"}
+	}),
+	newRoute(http.MethodGet, "lol/static-data/v3/maps", "GET_getMapData", func(r *request) interface{} {
+		return &ionia.MapDataDTO{
+			Data:    map[string]ionia.MapDetailsDTO{"11": {MapName: "Summoner's Rift", MapID: 11}},
+			Version: version,
+			Type:    "map",
+		}
+	}),
+	newRoute(http.MethodGet, "lol/static-data/v3/masteries", "GET_getMasteryList", func(r *request) interface{} {
+		return &ionia.MasteryListDTO{Data: map[string]ionia.MasteryDTO{"6111": {ID: 6111, Name: "Fury"}}, Version: version, Type: "mastery"}
+	}),
+	newRoute(http.MethodGet, "lol/static-data/v3/masteries/{id}", "GET_getMasteryById", func(r *request) interface{} {
+		return &ionia.MasteryDTO{ID: int(r.int64Param("id")), Name: "Mastery " + r.params["id"]}
+	}),
+	newRoute(http.MethodGet, "lol/static-data/v3/profile-icons", "GET_getProfileIcons", func(r *request) interface{} {
+		return &ionia.ProfileIconDataDTO{Data: map[string]ionia.ProfileIconDetailsDTO{"0": {ID: 0}}, Version: version, Type: "profileicon"}
+	}),
+	newRoute(http.MethodGet, "lol/static-data/v3/realms", "GET_getRealm", func(r *request) interface{} {
+		return &ionia.RealmDTO{L: "en_US", V: version, Dd: version, Lg: version, CDN: "https://ddragon.leagueoflegends.com/cdn"}
+	}),
+	newRoute(http.MethodGet, "lol/static-data/v3/reforged-rune-paths", "GET_getReforgedRunePaths", func(r *request) interface{} {
+		return []ionia.ReforgedRunePathDTO{runePath(8000), runePath(8100)}
+	}),
+	newRoute(http.MethodGet, "lol/static-data/v3/reforged-rune-paths/{id}", "GET_getReforgedRunePathById", func(r *request) interface{} {
+		p := runePath(int(r.int64Param("id")))
+		return &p
+	}),
+	newRoute(http.MethodGet, "lol/static-data/v3/reforged-runes", "GET_getReforgedRunes", func(r *request) interface{} {
+		return []ionia.ReforgedRuneDTO{reforgedRune(8005), reforgedRune(8112)}
+	}),
+	newRoute(http.MethodGet, "lol/static-data/v3/reforged-runes/{id}", "GET_getReforgedRuneById", func(r *request) interface{} {
+		rr := reforgedRune(int(r.int64Param("id")))
+		return &rr
+	}),
+	newRoute(http.MethodGet, "lol/static-data/v3/runes", "GET_getRuneList", func(r *request) interface{} {
+		return &ionia.RuneListDTO{Data: map[string]ionia.RuneDTO{"5001": {ID: 5001, Name: "Lesser Mark of Attack Damage"}}, Version: version, Type: "rune"}
+	}),
+	newRoute(http.MethodGet, "lol/static-data/v3/runes/{id}", "GET_getRuneById", func(r *request) interface{} {
+		return &ionia.RuneDTO{ID: int(r.int64Param("id")), Name: "Rune " + r.params["id"]}
+	}),
+	newRoute(http.MethodGet, "lol/static-data/v3/summoner-spells", "GET_getSummonerSpellList", func(r *request) interface{} {
+		return &ionia.SummonerSpellListDTO{
+			Data: map[string]ionia.SummonerSpellDTO{
+				"SummonerFlash": {ID: 4, Key: "SummonerFlash", Name: "Flash"},
+				"SummonerDot":   {ID: 14, Key: "SummonerDot", Name: "Ignite"},
+			},
+			Version: version,
+			Type:    "summoner",
+		}
+	}),
+	newRoute(http.MethodGet, "lol/static-data/v3/summoner-spells/{id}", "GET_getSummonerSpellById", func(r *request) interface{} {
+		return &ionia.SummonerSpellDTO{ID: int(r.int64Param("id")), Name: "Spell " + r.params["id"]}
+	}),
+	newRoute(http.MethodGet, "lol/static-data/v3/tarball-links", "GET_getTarballLinks", func(r *request) interface{} {
+		return "https://ddragon.leagueoflegends.com/cdn/dragontail-" + version + ".tgz"
+	}),
+	newRoute(http.MethodGet, "lol/static-data/v3/versions", "GET_getVersions", func(r *request) interface{} {
+		return []string{version, "8.11.1"}
+	}),
+
+	// LOL-Status-V3
+	newRoute(http.MethodGet, "lol/status/v3/shard-data", "GET_getShardData", func(r *request) interface{} {
+		return &ionia.ShardStatus{
+			Name:      "North America",
+			RegionTag: "na1",
+			HostName:  "prod.na1.lol.riotgames.com",
+			Slug:      "na",
+			Locales:   []string{"en_US"},
+			Services: []ionia.Service{
+				{Name: "Game", Slug: "game", Status: "online"},
+				{Name: "Store", Slug: "store", Status: "online"},
+			},
+		}
+	}),
+
+	// Match-V3
+	newRoute(http.MethodGet, "lol/match/v3/matches/by-tournament-code/{tournamentCode}/ids", "GET_getMatchIdsByTournamentCode", func(r *request) interface{} {
+		return []int64{2800000000}
+	}),
+	newRoute(http.MethodGet, "lol/match/v3/matches/{matchId}/by-tournament-code/{tournamentCode}", "GET_getMatchByTournamentCode", func(r *request) interface{} {
+		return match(r.int64Param("matchId"))
+	}),
+	newRoute(http.MethodGet, "lol/match/v3/matches/{matchId}", "GET_getMatch", func(r *request) interface{} {
+		return match(r.int64Param("matchId"))
+	}),
+	newRoute(http.MethodGet, "lol/match/v3/matchlists/by-account/{accountId}/recent/", "GET_getRecentMatchlist", func(r *request) interface{} {
+		return matchlist(20)
+	}),
+	newRoute(http.MethodGet, "lol/match/v3/matchlists/by-account/{accountId}", "GET_getMatchlist", func(r *request) interface{} {
+		return matchlist(100)
+	}),
+	newRoute(http.MethodGet, "lol/match/v3/timelines/by-match/{matchId}", "GET_getMatchTimeline", func(r *request) interface{} {
+		return timeline()
+	}),
+
+	// Spectator-V3
+	newRoute(http.MethodGet, "lol/spectator/v3/active-games/by-summoner/{summonerId}", "GET_getCurrentGameInfoBySummoner", func(r *request) interface{} {
+		return currentGame(r.int64Param("summonerId"))
+	}),
+	newRoute(http.MethodGet, "lol/spectator/v3/featured-games", "GET_getFeaturedGames", func(r *request) interface{} {
+		return featuredGames()
+	}),
+
+	// Summoner-V3
+	newRoute(http.MethodGet, "lol/summoner/v3/summoners/by-account/{accountId}", "GET_getByAccountId", func(r *request) interface{} {
+		return summoner(r.int64Param("accountId")-accountIDOffset, "")
+	}),
+	newRoute(http.MethodGet, "lol/summoner/v3/summoners/by-name/{summonerName}", "GET_getBySummonerName", func(r *request) interface{} {
+		return summoner(int64(r.id), r.params["summonerName"])
+	}),
+	newRoute(http.MethodGet, "lol/summoner/v3/summoners/{summonerId}", "GET_getBySummonerId", func(r *request) interface{} {
+		return summoner(r.int64Param("summonerId"), "")
+	}),
+
+	// Third-Party-Code-V3
+	newRoute(http.MethodGet, "lol/platform/v3/third-party-code/by-summoner/{summonerId}", "GET_getThirdPartyCodeBySummonerId", func(r *request) interface{} {
+		return "code-" + r.params["summonerId"]
+	}),
+
+	// Tournament-Stub-V3
+	newRoute(http.MethodPost, "lol/tournament-stub/v3/codes", "POST_createTournamentCode", tournamentCodes),
+	newRoute(http.MethodGet, "lol/tournament-stub/v3/lobby-events/by-code/{tournamentCode}", "GET_getLobbyEventsByCode", lobbyEvents),
+	newRoute(http.MethodPost, "lol/tournament-stub/v3/providers", "POST_registerProviderData", func(r *request) interface{} {
+		return r.id
+	}),
+	newRoute(http.MethodPost, "lol/tournament-stub/v3/tournaments", "POST_registerTournament", func(r *request) interface{} {
+		return r.id
+	}),
+
+	// Tournament-V3
+	newRoute(http.MethodPost, "lol/tournament/v3/codes", "POST_createTournamentCode", tournamentCodes),
+	newRoute(http.MethodPut, "lol/tournament/v3/codes/{tournamentCode}", "PUT_updateCode", func(r *request) interface{} {
+		return nil
+	}),
+	newRoute(http.MethodGet, "lol/tournament/v3/codes/{tournamentCode}", "GET_getTournamentCode", func(r *request) interface{} {
+		return &ionia.TournamentCodeDTO{
+			Map:          "SUMMONERS_RIFT",
+			Code:         r.params["tournamentCode"],
+			Spectators:   "ALL",
+			Region:       "NA",
+			ProviderID:   1,
+			TeamSize:     5,
+			PickType:     "TOURNAMENT_DRAFT",
+			TournamentID: 1,
+			LobbyName:    "lobby",
+			Password:     "password",
+			ID:           r.id,
+		}
+	}),
+	newRoute(http.MethodGet, "lol/tournament/v3/lobby-events/by-code/{tournamentCode}", "GET_getLobbyEventsByCode", lobbyEvents),
+	newRoute(http.MethodPost, "lol/tournament/v3/providers", "POST_registerProviderData", func(r *request) interface{} {
+		return r.id
+	}),
+	newRoute(http.MethodPost, "lol/tournament/v3/tournaments", "POST_registerTournament", func(r *request) interface{} {
+		return r.id
+	}),
+}
+
+const version = "8.12.1"
+
+// Account IDs of fixture summoners are derived from their summoner IDs.
+const accountIDOffset = 200000000
+
+var championNames = map[int]string{
+	1:   "Annie",
+	17:  "Teemo",
+	103: "Ahri",
+}
+
+func championName(id int) string {
+	if name, ok := championNames[id]; ok {
+		return name
+	}
+	return "Champion" + strconv.Itoa(id)
+}
+
+func champion(id int64) ionia.ChampionDTO {
+	return ionia.ChampionDTO{
+		RankedPlayEnabled: true,
+		BotEnabled:        true,
+		BotMmEnabled:      true,
+		Active:            true,
+		FreeToPlay:        id%2 == 1,
+		ID:                id,
+	}
+}
+
+func staticChampion(id int) ionia.StaticChampionDTO {
+	name := championName(id)
+	return ionia.StaticChampionDTO{ID: id, Key: name, Name: name, Title: "the " + name}
+}
+
+func mastery(summonerID, championID int64) ionia.ChampionMasteryDTO {
+	return ionia.ChampionMasteryDTO{
+		ChestGranted:                 true,
+		ChampionLevel:                5,
+		ChampionPoints:               34356,
+		ChampionID:                   championID,
+		PlayerID:                     summonerID,
+		ChampionPointsSinceLastLevel: 12756,
+		LastPlayTime:                 1527000000000,
+	}
+}
+
+func league(tier, queue string) *ionia.LeagueListDTO {
+	return &ionia.LeagueListDTO{
+		LeagueID: "00000000-0000-0000-0000-000000000000",
+		Tier:     tier,
+		Queue:    queue,
+		Name:     "Ionia's Fakers",
+		Entries: []ionia.LeagueItemDTO{
+			{Rank: "I", PlayerOrTeamName: "Summoner 1", PlayerOrTeamID: "1", LeaguePoints: 900, Wins: 300, Losses: 250},
+			{Rank: "I", PlayerOrTeamName: "Summoner 2", PlayerOrTeamID: "2", LeaguePoints: 850, Wins: 280, Losses: 240},
+		},
+	}
+}
+
+func item(id int) ionia.ItemDTO {
+	return ionia.ItemDTO{
+		ID:   id,
+		Name: "Item " + strconv.Itoa(id),
+		Gold: ionia.GoldDTO{Base: 300, Total: 300, Sell: 210, Purchasable: true},
+	}
+}
+
+func runePath(id int) ionia.ReforgedRunePathDTO {
+	names := map[int]string{8000: "Precision", 8100: "Domination", 8200: "Sorcery", 8300: "Inspiration", 8400: "Resolve"}
+	name, ok := names[id]
+	if !ok {
+		name = "Path " + strconv.Itoa(id)
+	}
+	return ionia.ReforgedRunePathDTO{ID: id, Key: name, Name: name}
+}
+
+func reforgedRune(id int) ionia.ReforgedRuneDTO {
+	path := runePath(id / 100 * 100)
+	return ionia.ReforgedRuneDTO{ID: id, Name: "Rune " + strconv.Itoa(id), RunePathID: path.ID, RunePathName: path.Name}
+}
+
+func summoner(id int64, name string) *ionia.SummonerDTO {
+	if name == "" {
+		name = "Summoner " + strconv.FormatInt(id, 10)
+	}
+	return &ionia.SummonerDTO{
+		ProfileIconID: 3,
+		Name:          name,
+		SummonerLevel: 30,
+		RevisionDate:  1527000000000,
+		ID:            id,
+		AccountID:     id + accountIDOffset,
+	}
+}
+
+// match returns a finished match between ten fixture summoners, won by the blue team.
+func match(id int64) *ionia.MatchDTO {
+	m := &ionia.MatchDTO{
+		SeasonID:     11,
+		QueueID:      420,
+		GameID:       id,
+		GameVersion:  version + ".224",
+		PlatformID:   "NA1",
+		GameMode:     "CLASSIC",
+		MapID:        11,
+		GameType:     "MATCHED_GAME",
+		GameDuration: 1800,
+		GameCreation: 1527000000000,
+		Teams: []ionia.TeamStatsDTO{
+			{TeamID: ionia.TeamBlue, Win: "Win", FirstBlood: true, FirstTower: true, TowerKills: 9, DragonKills: 2, BaronKills: 1},
+			{TeamID: ionia.TeamRed, Win: "Fail", TowerKills: 3, DragonKills: 1, RiftHeraldKills: 1},
+		},
+	}
+
+	for i := 1; i <= 10; i++ {
+		team := ionia.TeamBlue
+		if i > 5 {
+			team = ionia.TeamRed
+		}
+		summonerID := int64(i)
+		m.ParticipantIdentities = append(m.ParticipantIdentities, ionia.ParticipantIdentityDTO{
+			ParticipantID: i,
+			Player: ionia.PlayerDTO{
+				CurrentPlatformID: "NA1",
+				PlatformID:        "NA1",
+				SummonerName:      "Summoner " + strconv.FormatInt(summonerID, 10),
+				SummonerID:        summonerID,
+				AccountID:         summonerID + accountIDOffset,
+				CurrentAccountID:  summonerID + accountIDOffset,
+			},
+		})
+		m.Participants = append(m.Participants, ionia.ParticipantDTO{
+			ParticipantID: i,
+			TeamID:        team,
+			ChampionID:    []int{1, 17, 103}[i%3],
+			Spell1ID:      4,
+			Spell2ID:      14,
+			Stats: ionia.ParticipantStatsDTO{
+				ParticipantID: i,
+				Win:           team == ionia.TeamBlue,
+				Kills:         i,
+				Deaths:        10 - i,
+				Assists:       5,
+				GoldEarned:    10000 + 100*i,
+				ChampLevel:    16,
+			},
+		})
+	}
+
+	return m
+}
+
+func matchlist(n int) *ionia.MatchlistDTO {
+	ml := &ionia.MatchlistDTO{TotalGames: n, EndIndex: n}
+	for i := 0; i < n; i++ {
+		ml.Matches = append(ml.Matches, ionia.MatchReferenceDTO{
+			Lane:       "MID",
+			GameID:     2800000000 - int64(i),
+			Champion:   103,
+			PlatformID: "NA1",
+			Season:     11,
+			Queue:      420,
+			Role:       "SOLO",
+			Timestamp:  1527000000000 - int64(i)*3600000,
+		})
+	}
+	return ml
+}
+
+func timeline() *ionia.MatchTimelineDTO {
+	frames := make([]ionia.MatchFrameDTO, 3)
+	for f := range frames {
+		frames[f].Timestamp = int64(f) * 60000
+		frames[f].ParticipantFrames = make(map[int]ionia.MatchParticipantFrameDTO)
+		for i := 1; i <= 10; i++ {
+			x, y := 500, 500
+			if i > 5 {
+				x, y = 14300, 14300
+			}
+			frames[f].ParticipantFrames[i] = ionia.MatchParticipantFrameDTO{
+				ParticipantID: i,
+				Level:         1 + f,
+				TotalGold:     500 + f*400,
+				CurrentGold:   500,
+				XP:            f * 300,
+				Position:      ionia.MatchPositionDTO{X: x, Y: y},
+			}
+		}
+	}
+	frames[1].Events = []ionia.MatchEventDTO{
+		{Type: "ITEM_PURCHASED", Timestamp: 2000, ParticipantID: 1, ItemID: 1055},
+		{Type: "WARD_PLACED", Timestamp: 50000, CreatorID: 2, WardType: "YELLOW_TRINKET"},
+	}
+	frames[2].Events = []ionia.MatchEventDTO{
+		{
+			Type:                    "CHAMPION_KILL",
+			Timestamp:               110000,
+			KillerID:                1,
+			VictimID:                6,
+			AssistingParticipantIds: []int{2},
+			Position:                ionia.MatchPositionDTO{X: 7000, Y: 7000},
+		},
+	}
+	return &ionia.MatchTimelineDTO{Frames: frames, FrameInterval: 60000}
+}
+
+func currentGame(summonerID int64) *ionia.CurrentGameInfo {
+	g := &ionia.CurrentGameInfo{
+		GameID:            3000000000 + summonerID,
+		GameStartTime:     1527000000000,
+		PlatformID:        "NA1",
+		GameMode:          "CLASSIC",
+		MapID:             11,
+		GameType:          "MATCHED_GAME",
+		GameQueueConfigID: 420,
+		GameLength:        600,
+		Observers:         ionia.Observer{EncryptionKey: fmt.Sprintf("key%d", summonerID)},
+	}
+	for i := int64(0); i < 10; i++ {
+		team := int64(ionia.TeamBlue)
+		if i >= 5 {
+			team = ionia.TeamRed
+		}
+		g.Participants = append(g.Participants, ionia.CurrentGameParticipant{
+			SummonerID:   summonerID + i,
+			SummonerName: "Summoner " + strconv.FormatInt(summonerID+i, 10),
+			ChampionID:   int64([]int{1, 17, 103}[i%3]),
+			TeamID:       team,
+			Spell1ID:     4,
+			Spell2ID:     14,
+			Perks:        ionia.Perks{PerkStyle: 8100, PerkSubStyle: 8000, PerkIDs: []int64{8112}},
+		})
+	}
+	return g
+}
+
+func featuredGames() *ionia.FeaturedGames {
+	fg := &ionia.FeaturedGames{ClientRefreshInterval: 300}
+	for i := int64(1); i <= 5; i++ {
+		g := currentGame(i * 100)
+		fgi := ionia.FeaturedGameInfo{
+			GameID:            g.GameID,
+			GameStartTime:     g.GameStartTime,
+			PlatformID:        g.PlatformID,
+			GameMode:          g.GameMode,
+			MapID:             g.MapID,
+			GameType:          g.GameType,
+			GameLength:        g.GameLength,
+			GameQueueConfigID: g.GameQueueConfigID,
+			Observers:         g.Observers,
+		}
+		for _, p := range g.Participants {
+			fgi.Participants = append(fgi.Participants, ionia.Participant{
+				ChampionID:   p.ChampionID,
+				SummonerName: p.SummonerName,
+				TeamID:       p.TeamID,
+				Spell1ID:     p.Spell1ID,
+				Spell2ID:     p.Spell2ID,
+			})
+		}
+		fg.GameList = append(fg.GameList, fgi)
+	}
+	return fg
+}
+
+func tournamentCodes(r *request) interface{} {
+	count, _ := strconv.Atoi(r.query.Get("count"))
+	if count < 1 {
+		count = 1
+	}
+
+	codes := make([]string, count)
+	for i := range codes {
+		codes[i] = fmt.Sprintf("NA%04d-%s-%d", r.id, r.query.Get("tournamentId"), i)
+	}
+	return codes
+}
+
+func lobbyEvents(r *request) interface{} {
+	return &ionia.LobbyEventDTOWrapper{
+		EventList: []ionia.LobbyEventDTO{
+			{EventType: "PracticeGameCreatedEvent", SummonerID: "1", Timestamp: "1527000000000"},
+			{EventType: "PlayerJoinedGameEvent", SummonerID: "1", Timestamp: "1527000001000"},
+		},
+	}
+}
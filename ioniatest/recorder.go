@@ -0,0 +1,175 @@
+package ioniatest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// redacted replaces the API key in recorded requests.
+const redacted = "REDACTED"
+
+// Recording is a request and response pair, as written by Recorder and read by Replayer.
+type Recording struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the request half of a Recording.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is the response half of a Recording.
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body"`
+}
+
+// Recorder is an http.RoundTripper which sends requests through Transport and writes
+// each request and its response to a file in Dir, with the X-Riot-Token header
+// redacted. Use it with ionia.WithTransport to capture responses from the Riot API:
+//
+//	c := ionia.NewClient(key, ionia.WithTransport(&ioniatest.Recorder{Dir: "testdata"}))
+//
+// Identical requests are written to the same file, so only the last response is kept.
+type Recorder struct {
+	// Transport sends the recorded requests. Default: http.DefaultTransport.
+	Transport http.RoundTripper
+
+	// Dir is the directory recordings are written to. It is created if it does not exist.
+	Dir string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	header := make(http.Header, len(req.Header))
+	for k, v := range req.Header {
+		header[k] = append([]string(nil), v...)
+	}
+	if header.Get("X-Riot-Token") != "" {
+		header.Set("X-Riot-Token", redacted)
+	}
+	rec := &Recording{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: header,
+			Body:   string(reqBody),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Body:       string(respBody),
+		},
+	}
+
+	b, err := json.MarshalIndent(rec, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(r.Dir, 0755); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(r.Dir, recordingName(req, reqBody)), b, 0644); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Replayer is an http.RoundTripper which answers requests with the recordings
+// written to Dir by a Recorder, without sending them. Requests which have not
+// been recorded fail with an error.
+type Replayer struct {
+	Dir string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	name := filepath.Join(r.Dir, recordingName(req, reqBody))
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("no recording for %s %s: %v", req.Method, req.URL, err)
+	}
+	rec := &Recording{}
+	if err := json.Unmarshal(b, rec); err != nil {
+		return nil, fmt.Errorf("reading recording %s: %v", name, err)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.Response.StatusCode, http.StatusText(rec.Response.StatusCode)),
+		StatusCode:    rec.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        rec.Response.Header,
+		Body:          ioutil.NopCloser(bytes.NewReader([]byte(rec.Response.Body))),
+		ContentLength: int64(len(rec.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// readBody reads the body of req and replaces it, so that it can still be sent.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	b, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(b))
+	return b, nil
+}
+
+var unsafeNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// recordingName returns the file name for a request. The name is readable, and ends
+// with a hash of the method, URL and body so that different requests never share a file.
+func recordingName(req *http.Request, body []byte) string {
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s %s\n", req.Method, req.URL)
+	h.Write(body)
+
+	name := unsafeNameChars.ReplaceAllString(req.Method+"_"+req.URL.Path, "_")
+	if len(name) > 100 {
+		name = name[:100]
+	}
+	return fmt.Sprintf("%s-%08x.json", name, h.Sum32())
+}
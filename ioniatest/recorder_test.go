@@ -0,0 +1,94 @@
+package ioniatest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/brattonross/ionia"
+)
+
+func TestRecorderAndReplayer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ioniatest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	srv := NewServer()
+	ts := httptest.NewServer(srv)
+	rc := ionia.NewClient("RGAPI-secret", ionia.WithTransport(&Recorder{Dir: dir}))
+	rc.BaseURL = NewClient(ts).BaseURL
+
+	want, _, err := rc.Summoner.BySummonerID(1)
+	if err != nil {
+		t.Fatalf("Summoner.BySummonerID returned error: %v", err)
+	}
+	if _, _, err := rc.Tournament.Provider(&ionia.ProviderRegistration{URL: "http://example.com", Region: "NA"}); err != nil {
+		t.Fatalf("Tournament.Provider returned error: %v", err)
+	}
+	ts.Close()
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 2 {
+		t.Fatalf("expected 2 recordings, got %d", len(files))
+	}
+	for _, f := range files {
+		b, _ := ioutil.ReadFile(f)
+		if strings.Contains(string(b), "RGAPI-secret") {
+			t.Errorf("recording %s contains the API key", f)
+		}
+	}
+
+	pc := ionia.NewClient("", ionia.WithTransport(&Replayer{Dir: dir}))
+	pc.BaseURL = rc.BaseURL
+
+	got, resp, err := pc.Summoner.BySummonerID(1)
+	if err != nil {
+		t.Fatalf("replayed Summoner.BySummonerID returned error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("replayed summoner %+v, want %+v", got, want)
+	}
+	if resp.Header.Get("X-App-Rate-Limit") == "" {
+		t.Error("replayed response is missing rate limit headers")
+	}
+
+	if _, _, err := pc.Tournament.Provider(&ionia.ProviderRegistration{URL: "http://example.com", Region: "NA"}); err != nil {
+		t.Errorf("replayed Tournament.Provider returned error: %v", err)
+	}
+	if _, _, err := pc.Tournament.Provider(&ionia.ProviderRegistration{URL: "http://example.com", Region: "EUW"}); err == nil {
+		t.Error("expected error replaying a request with a different body")
+	}
+	if _, _, err := pc.Summoner.BySummonerID(2); err == nil {
+		t.Error("expected error replaying a request which was not recorded")
+	}
+}
+
+func TestRecorderRecordsErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ioniatest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	srv := NewServer()
+	srv.FailNext("", http.StatusNotFound, 1)
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	c := NewClient(ts, ionia.WithTransport(&Recorder{Dir: dir}))
+	if _, _, err := c.Spectator.CurrentGame(1); err == nil {
+		t.Fatal("expected error from scripted failure")
+	}
+
+	c = NewClient(ts, ionia.WithTransport(&Replayer{Dir: dir}))
+	if _, resp, err := c.Spectator.CurrentGame(1); err == nil || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected replayed 404, got %v", err)
+	}
+}
@@ -0,0 +1,361 @@
+// Package ioniatest provides an in-process stand-in for the Riot API,
+// for testing code which uses the ionia client without network access.
+//
+// A Server serves every endpoint implemented by the ionia client from fixture
+// data, counts requests against application and method rate limits the same
+// way the Riot API does, and lets tests override responses or script errors:
+//
+//	srv := ioniatest.NewServer()
+//	ts := httptest.NewServer(srv)
+//	defer ts.Close()
+//
+//	client := ioniatest.NewClient(ts)
+//	srv.SetResponse("/lol/summoner/v3/summoners/by-name/Doublelift", &ionia.SummonerDTO{Name: "Doublelift"})
+//	srv.FailNext("/lol/match/v3/matches/1", http.StatusServiceUnavailable, 2)
+package ioniatest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brattonross/ionia"
+)
+
+// Default rate limits, matching those of a Riot development key.
+const (
+	DefaultAppRateLimit    = "20:1,100:120"
+	DefaultMethodRateLimit = "1000:10"
+)
+
+// Server is a fake Riot API. It implements http.Handler, and is usually
+// served with httptest.NewServer.
+type Server struct {
+	// The application rate limit, formatted as in the X-App-Rate-Limit header.
+	// An empty string disables the application rate limit.
+	AppRateLimit string
+
+	// Method rate limits keyed by method name (e.g. GET_getBySummonerName),
+	// formatted as in the X-Method-Rate-Limit header. Methods without an
+	// entry use DefaultMethodRateLimit.
+	MethodRateLimits map[string]string
+
+	// Now returns the current time, and is used to count rate limit windows.
+	// Default: time.Now.
+	Now func() time.Time
+
+	// Tournaments, if set, serves the tournament endpoints and match IDs by
+	// tournament code in place of the default fixtures.
+	Tournaments *TournamentSimulator
+
+	mu        sync.Mutex
+	responses map[string]interface{}
+	failures  map[string][]int
+	windows   map[string]*window
+	requests  map[string]int
+	nextID    int
+}
+
+// window counts requests made in a single rate limit window.
+type window struct {
+	start time.Time
+	count int
+}
+
+// NewServer creates a server with development key rate limits and default fixtures.
+func NewServer() *Server {
+	return &Server{
+		AppRateLimit:     DefaultAppRateLimit,
+		MethodRateLimits: make(map[string]string),
+		Now:              time.Now,
+		responses:        make(map[string]interface{}),
+		failures:         make(map[string][]int),
+		windows:          make(map[string]*window),
+		requests:         make(map[string]int),
+	}
+}
+
+// NewClient creates an ionia client which sends its requests to the given test server.
+func NewClient(ts *httptest.Server, opts ...ionia.ClientOption) *ionia.Client {
+	c := ionia.NewClient("ioniatest", opts...)
+	c.BaseURL, _ = url.Parse(ts.URL + "/")
+	return c
+}
+
+// SetResponse overrides the response for requests to the given path (e.g.
+// /lol/summoner/v3/summoners/42) with v encoded as JSON. If v is a []byte or
+// json.RawMessage, it is written as is. A nil v restores the default fixture.
+func (s *Server) SetResponse(path string, v interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v == nil {
+		delete(s.responses, path)
+		return
+	}
+	s.responses[path] = v
+}
+
+// FailNext makes the next n requests to the given path respond with the given status code.
+// An empty path matches requests to any path. Scripted failures still count against
+// the rate limits, as they do on the Riot API.
+func (s *Server) FailNext(path string, status, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < n; i++ {
+		s.failures[path] = append(s.failures[path], status)
+	}
+}
+
+// Requests returns the number of requests received for each method name.
+func (s *Server) Requests() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := make(map[string]int, len(s.requests))
+	for k, v := range s.requests {
+		r[k] = v
+	}
+	return r
+}
+
+// Reset clears overridden responses, scripted failures, rate limit counts and request counts.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses = make(map[string]interface{})
+	s.failures = make(map[string][]int)
+	s.windows = make(map[string]*window)
+	s.requests = make(map[string]int)
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rt, params := matchRoute(r.Method, r.URL.Path)
+	if rt == nil {
+		writeStatus(w, http.StatusNotFound)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests[rt.name]++
+
+	methodLimit := s.MethodRateLimits[rt.name]
+	if methodLimit == "" {
+		methodLimit = DefaultMethodRateLimit
+	}
+	if !s.countRequest(w, rt.name, methodLimit) {
+		return
+	}
+
+	if status, ok := s.nextFailure(r.URL.Path); ok {
+		writeStatus(w, status)
+		return
+	}
+
+	if s.Tournaments != nil {
+		if h := s.Tournaments.handler(r.Method, r.URL.Path); h != nil {
+			h(w, r)
+			return
+		}
+	}
+
+	var body interface{}
+	if v, ok := s.responses[r.URL.Path]; ok {
+		body = v
+	} else {
+		s.nextID++
+		body = rt.fixture(&request{params: params, query: r.URL.Query(), id: s.nextID})
+	}
+
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	switch b := body.(type) {
+	case []byte:
+		w.Write(b)
+	case json.RawMessage:
+		w.Write(b)
+	case nil:
+		w.WriteHeader(http.StatusOK)
+	default:
+		json.NewEncoder(w).Encode(b)
+	}
+}
+
+func (s *Server) nextFailure(path string) (int, bool) {
+	for _, p := range []string{path, ""} {
+		if f := s.failures[p]; len(f) > 0 {
+			s.failures[p] = f[1:]
+			return f[0], true
+		}
+	}
+	return 0, false
+}
+
+// countRequest counts the request against the application and method limits and
+// writes the rate limit headers. If a limit is exceeded, a 429 is written and
+// false is returned.
+func (s *Server) countRequest(w http.ResponseWriter, method, methodLimit string) bool {
+	now := s.Now()
+	appLimits := parseLimits(s.AppRateLimit)
+	methodLimits := parseLimits(methodLimit)
+
+	// Requests are only counted if every window has room, as on the Riot API.
+	limitType, retryAfter := "", time.Duration(0)
+	check := func(key string, limits []limit, typ string) {
+		for _, l := range limits {
+			win := s.window(key, l, now)
+			if win.count >= l.allowed {
+				if wait := win.start.Add(l.period).Sub(now); wait > retryAfter {
+					limitType, retryAfter = typ, wait
+				}
+			}
+		}
+	}
+	check("app", appLimits, "application")
+	check(method, methodLimits, "method")
+
+	if limitType == "" {
+		for _, l := range appLimits {
+			s.window("app", l, now).count++
+		}
+		for _, l := range methodLimits {
+			s.window(method, l, now).count++
+		}
+	}
+
+	if s.AppRateLimit != "" {
+		w.Header().Set("X-App-Rate-Limit", s.AppRateLimit)
+		w.Header().Set("X-App-Rate-Limit-Count", s.counts("app", appLimits, now))
+	}
+	w.Header().Set("X-Method-Rate-Limit", methodLimit)
+	w.Header().Set("X-Method-Rate-Limit-Count", s.counts(method, methodLimits, now))
+
+	if limitType != "" {
+		seconds := int((retryAfter + time.Second - 1) / time.Second)
+		w.Header().Set("X-Rate-Limit-Type", limitType)
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+		writeStatus(w, http.StatusTooManyRequests)
+		return false
+	}
+	return true
+}
+
+func (s *Server) window(key string, l limit, now time.Time) *window {
+	k := key + ":" + strconv.Itoa(int(l.period/time.Second))
+	win, ok := s.windows[k]
+	if !ok || now.Sub(win.start) >= l.period {
+		win = &window{start: now}
+		s.windows[k] = win
+	}
+	return win
+}
+
+func (s *Server) counts(key string, limits []limit, now time.Time) string {
+	parts := make([]string, len(limits))
+	for i, l := range limits {
+		parts[i] = fmt.Sprintf("%d:%d", s.window(key, l, now).count, int(l.period/time.Second))
+	}
+	return strings.Join(parts, ",")
+}
+
+type limit struct {
+	allowed int
+	period  time.Duration
+}
+
+// parseLimits parses a rate limit header value (e.g. 20:1,100:120),
+// ordered from the shortest window to the longest.
+func parseLimits(s string) []limit {
+	var limits []limit
+	for _, part := range strings.Split(s, ",") {
+		fields := strings.Split(part, ":")
+		if len(fields) != 2 {
+			continue
+		}
+		allowed, err1 := strconv.Atoi(fields[0])
+		seconds, err2 := strconv.Atoi(fields[1])
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		limits = append(limits, limit{allowed, time.Duration(seconds) * time.Second})
+	}
+	sort.Slice(limits, func(i, j int) bool { return limits[i].period < limits[j].period })
+	return limits
+}
+
+func writeStatus(w http.ResponseWriter, status int) {
+	writeError(w, status, http.StatusText(status))
+}
+
+// writeError writes an error body in the format used by the Riot API.
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `{"status": {"message": %q, "status_code": %d}}`, message, status)
+}
+
+// request holds the parts of a request used to build a fixture.
+type request struct {
+	params map[string]string
+	query  url.Values
+	id     int
+}
+
+func (r *request) int64Param(name string) int64 {
+	v, _ := strconv.ParseInt(r.params[name], 10, 64)
+	return v
+}
+
+// route is an endpoint implemented by the fake API.
+type route struct {
+	method  string
+	pattern []string
+	name    string
+	fixture func(*request) interface{}
+}
+
+// matchRoute finds the route for a request, returning the values of its path parameters.
+func matchRoute(method, path string) (*route, map[string]string) {
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i := range routes {
+		rt := &routes[i]
+		if rt.method != method || len(rt.pattern) != len(segments) {
+			continue
+		}
+
+		params := make(map[string]string)
+		matched := true
+		for j, p := range rt.pattern {
+			if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
+				v, err := url.PathUnescape(segments[j])
+				if err != nil || v == "" {
+					matched = false
+					break
+				}
+				params[p[1:len(p)-1]] = v
+			} else if p != segments[j] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return rt, params
+		}
+	}
+	return nil, nil
+}
+
+func newRoute(method, pattern, name string, fixture func(*request) interface{}) route {
+	return route{
+		method:  method,
+		pattern: strings.Split(pattern, "/"),
+		name:    name,
+		fixture: fixture,
+	}
+}
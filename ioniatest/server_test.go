@@ -0,0 +1,221 @@
+package ioniatest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/brattonross/ionia"
+)
+
+func createTestServer() (*Server, *ionia.Client, func()) {
+	srv := NewServer()
+	srv.AppRateLimit = ""
+	ts := httptest.NewServer(srv)
+	return srv, NewClient(ts), ts.Close
+}
+
+var validTournamentCode = &ionia.TournamentCode{
+	MapType:       ionia.MapTypeSummonersRift,
+	PickType:      ionia.PickTypeTournamentDraft,
+	SpectatorType: ionia.SpectatorTypeAll,
+	TeamSize:      5,
+}
+
+func TestServerEndpoints(t *testing.T) {
+	_, c, teardown := createTestServer()
+	defer teardown()
+
+	tt := []struct {
+		name string
+		call func() (*http.Response, error)
+	}{
+		{"Champion.All", func() (*http.Response, error) { _, r, err := c.Champion.All(); return r, err }},
+		{"Champion.ByID", func() (*http.Response, error) { _, r, err := c.Champion.ByID(17); return r, err }},
+		{"ChampionMastery.MasteryBySummonerID", func() (*http.Response, error) { _, r, err := c.ChampionMastery.MasteryBySummonerID(1); return r, err }},
+		{"ChampionMastery.BySummonerAndChampionID", func() (*http.Response, error) {
+			_, r, err := c.ChampionMastery.BySummonerAndChampionID(1, 17)
+			return r, err
+		}},
+		{"ChampionMastery.ScoreBySummonerID", func() (*http.Response, error) { _, r, err := c.ChampionMastery.ScoreBySummonerID(1); return r, err }},
+		{"League.ChallengerLeagueByQueue", func() (*http.Response, error) {
+			_, r, err := c.League.ChallengerLeagueByQueue("RANKED_SOLO_5x5")
+			return r, err
+		}},
+		{"League.ByLeagueID", func() (*http.Response, error) { _, r, err := c.League.ByLeagueID("abc"); return r, err }},
+		{"League.MasterLeagueByQueue", func() (*http.Response, error) {
+			_, r, err := c.League.MasterLeagueByQueue("RANKED_SOLO_5x5")
+			return r, err
+		}},
+		{"League.PositionsBySummonerID", func() (*http.Response, error) { _, r, err := c.League.PositionsBySummonerID(1); return r, err }},
+		{"StaticData.Champions", func() (*http.Response, error) { _, r, err := c.StaticData.Champions(); return r, err }},
+		{"StaticData.StreamChampions", func() (*http.Response, error) {
+			_, r, err := c.StaticData.StreamChampions(func(string, *ionia.StaticChampionDTO) error { return nil })
+			return r, err
+		}},
+		{"StaticData.ChampionByID", func() (*http.Response, error) { _, r, err := c.StaticData.ChampionByID(1); return r, err }},
+		{"StaticData.Items", func() (*http.Response, error) { _, r, err := c.StaticData.Items(); return r, err }},
+		{"StaticData.StreamItems", func() (*http.Response, error) {
+			_, r, err := c.StaticData.StreamItems(func(string, *ionia.ItemDTO) error { return nil })
+			return r, err
+		}},
+		{"StaticData.ItemByID", func() (*http.Response, error) { _, r, err := c.StaticData.ItemByID(1001); return r, err }},
+		{"StaticData.LanguageStrings", func() (*http.Response, error) { _, r, err := c.StaticData.LanguageStrings(); return r, err }},
+		{"StaticData.Languages", func() (*http.Response, error) { _, r, err := c.StaticData.Languages(); return r, err }},
+		{"StaticData.Maps", func() (*http.Response, error) { _, r, err := c.StaticData.Maps(); return r, err }},
+		{"StaticData.Masteries", func() (*http.Response, error) { _, r, err := c.StaticData.Masteries(); return r, err }},
+		{"StaticData.MasteryByID", func() (*http.Response, error) { _, r, err := c.StaticData.MasteryByID(6111); return r, err }},
+		{"StaticData.ProfileIcons", func() (*http.Response, error) { _, r, err := c.StaticData.ProfileIcons(); return r, err }},
+		{"StaticData.Realms", func() (*http.Response, error) { _, r, err := c.StaticData.Realms(); return r, err }},
+		{"StaticData.ReforgedRunePaths", func() (*http.Response, error) { _, r, err := c.StaticData.ReforgedRunePaths(); return r, err }},
+		{"StaticData.ReforgedRunePathByID", func() (*http.Response, error) { _, r, err := c.StaticData.ReforgedRunePathByID(8100); return r, err }},
+		{"StaticData.ReforgedRunes", func() (*http.Response, error) { _, r, err := c.StaticData.ReforgedRunes(); return r, err }},
+		{"StaticData.ReforgedRuneByID", func() (*http.Response, error) { _, r, err := c.StaticData.ReforgedRuneByID(8112); return r, err }},
+		{"StaticData.Runes", func() (*http.Response, error) { _, r, err := c.StaticData.Runes(); return r, err }},
+		{"StaticData.RuneByID", func() (*http.Response, error) { _, r, err := c.StaticData.RuneByID(5001); return r, err }},
+		{"StaticData.SummonerSpells", func() (*http.Response, error) { _, r, err := c.StaticData.SummonerSpells(); return r, err }},
+		{"StaticData.SummonerSpellByID", func() (*http.Response, error) { _, r, err := c.StaticData.SummonerSpellByID(4); return r, err }},
+		{"StaticData.Versions", func() (*http.Response, error) { _, r, err := c.StaticData.Versions(); return r, err }},
+		{"Status.ShardData", func() (*http.Response, error) { _, r, err := c.Status.ShardData(); return r, err }},
+		{"Match.MatchByID", func() (*http.Response, error) { _, r, err := c.Match.MatchByID(1); return r, err }},
+		{"Match.MatchesByAccountID", func() (*http.Response, error) { _, r, err := c.Match.MatchesByAccountID(1); return r, err }},
+		{"Match.RecentMatches", func() (*http.Response, error) { _, r, err := c.Match.RecentMatches(1); return r, err }},
+		{"Match.MatchTimelineByID", func() (*http.Response, error) { _, r, err := c.Match.MatchTimelineByID(1); return r, err }},
+		{"Match.StreamTimelineByID", func() (*http.Response, error) {
+			_, r, err := c.Match.StreamTimelineByID(1, func(*ionia.MatchFrameDTO) error { return nil })
+			return r, err
+		}},
+		{"Match.MatchIDsByTournamentCode", func() (*http.Response, error) { _, r, err := c.Match.MatchIDsByTournamentCode("CODE"); return r, err }},
+		{"Match.MatchByIDAndTournamentCode", func() (*http.Response, error) {
+			_, r, err := c.Match.MatchByIDAndTournamentCode(1, "CODE")
+			return r, err
+		}},
+		{"Spectator.CurrentGame", func() (*http.Response, error) { _, r, err := c.Spectator.CurrentGame(1); return r, err }},
+		{"Spectator.FeaturedGames", func() (*http.Response, error) { _, r, err := c.Spectator.FeaturedGames(); return r, err }},
+		{"Summoner.ByAccountID", func() (*http.Response, error) { _, r, err := c.Summoner.ByAccountID(200000001); return r, err }},
+		{"Summoner.BySummonerName", func() (*http.Response, error) { _, r, err := c.Summoner.BySummonerName("Doublelift"); return r, err }},
+		{"Summoner.BySummonerID", func() (*http.Response, error) { _, r, err := c.Summoner.BySummonerID(1); return r, err }},
+		{"TournamentStub.Codes", func() (*http.Response, error) {
+			_, r, err := c.TournamentStub.Codes(validTournamentCode)
+			return r, err
+		}},
+		{"TournamentStub.LobbyEventsByCode", func() (*http.Response, error) { _, r, err := c.TournamentStub.LobbyEventsByCode("CODE"); return r, err }},
+		{"TournamentStub.Provider", func() (*http.Response, error) {
+			_, r, err := c.TournamentStub.Provider(&ionia.ProviderRegistration{Region: "NA"})
+			return r, err
+		}},
+		{"TournamentStub.Tournament", func() (*http.Response, error) {
+			_, r, err := c.TournamentStub.Tournament(&ionia.TournamentRegistration{ProviderID: 1})
+			return r, err
+		}},
+		{"Tournament.Codes", func() (*http.Response, error) {
+			_, r, err := c.Tournament.Codes(validTournamentCode)
+			return r, err
+		}},
+		{"Tournament.UpdateTournament", func() (*http.Response, error) {
+			return c.Tournament.UpdateTournament("CODE", &ionia.TournamentCodeUpdate{MapType: ionia.MapTypeSummonersRift})
+		}},
+		{"Tournament.TournamentCode", func() (*http.Response, error) { _, r, err := c.Tournament.TournamentCode("CODE"); return r, err }},
+		{"Tournament.LobbyEvents", func() (*http.Response, error) { _, r, err := c.Tournament.LobbyEvents("CODE"); return r, err }},
+		{"Tournament.Provider", func() (*http.Response, error) {
+			_, r, err := c.Tournament.Provider(&ionia.ProviderRegistration{Region: "NA"})
+			return r, err
+		}},
+		{"Tournament.Tournament", func() (*http.Response, error) {
+			_, r, err := c.Tournament.Tournament(&ionia.TournamentRegistration{ProviderID: 1})
+			return r, err
+		}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := tc.call()
+			if err != nil {
+				t.Fatalf("%s returned error: %v", tc.name, err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("%s returned status %d", tc.name, resp.StatusCode)
+			}
+			if resp.Header.Get("X-Method-Rate-Limit") == "" {
+				t.Errorf("%s response is missing rate limit headers", tc.name)
+			}
+		})
+	}
+}
+
+func TestServerSetResponseAndFailNext(t *testing.T) {
+	srv, c, teardown := createTestServer()
+	defer teardown()
+
+	srv.SetResponse("/lol/summoner/v3/summoners/by-name/Doublelift", &ionia.SummonerDTO{ID: 7, Name: "Doublelift"})
+	s, _, err := c.Summoner.BySummonerName("Doublelift")
+	if err != nil {
+		t.Fatalf("Summoner.BySummonerName returned error: %v", err)
+	}
+	if s.ID != 7 || s.Name != "Doublelift" {
+		t.Errorf("unexpected summoner: %+v", s)
+	}
+
+	srv.FailNext("/lol/match/v3/matches/1", http.StatusServiceUnavailable, 1)
+	if _, resp, err := c.Match.MatchByID(1); err == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected scripted 503, got %v", err)
+	}
+	if _, _, err := c.Match.MatchByID(1); err != nil {
+		t.Errorf("Match.MatchByID returned error after scripted failure: %v", err)
+	}
+
+	if n := srv.Requests()["GET_getMatch"]; n != 2 {
+		t.Errorf("expected 2 GET_getMatch requests, got %d", n)
+	}
+}
+
+func TestServerRateLimit(t *testing.T) {
+	srv, c, teardown := createTestServer()
+	defer teardown()
+
+	now := time.Unix(0, 0)
+	srv.Now = func() time.Time { return now }
+	srv.AppRateLimit = "2:1,3:10"
+
+	// The client would stop sending once the limits are reached, so send requests directly.
+	get := func() *http.Response {
+		resp, err := http.Get(c.BaseURL.String() + "lol/summoner/v3/summoners/1")
+		if err != nil {
+			t.Fatalf("GET returned error: %v", err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	for i := 0; i < 2; i++ {
+		resp := get()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d", resp.StatusCode)
+		}
+		if got, want := resp.Header.Get("X-App-Rate-Limit-Count"), []string{"1:1,1:10", "2:1,2:10"}[i]; got != want {
+			t.Errorf("X-App-Rate-Limit-Count = %q, want %q", got, want)
+		}
+	}
+
+	resp := get()
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("X-Rate-Limit-Type"); got != "application" {
+		t.Errorf("X-Rate-Limit-Type = %q, want application", got)
+	}
+	if got := resp.Header.Get("Retry-After"); got != "1" {
+		t.Errorf("Retry-After = %q, want 1", got)
+	}
+
+	// The one second window resets, but the ten second window only has room for one more.
+	now = now.Add(time.Second)
+	if resp := get(); resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 after window reset, got %d", resp.StatusCode)
+	}
+	resp = get()
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") != "9" {
+		t.Errorf("expected 429 with Retry-After 9, got %d with %q", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+}
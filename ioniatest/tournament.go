@@ -0,0 +1,469 @@
+package ioniatest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brattonross/ionia"
+)
+
+// Legal values for the tournament registration and code endpoints.
+var (
+	tournamentRegions = map[string]string{
+		"BR": "BR1", "EUNE": "EUN1", "EUW": "EUW1", "JP": "JP1", "LAN": "LA1", "LAS": "LA2",
+		"NA": "NA1", "OCE": "OC1", "PBE": "PBE1", "RU": "RU", "TR": "TR1",
+	}
+	tournamentMaps = map[ionia.MapType]int{
+		ionia.MapTypeSummonersRift:   11,
+		ionia.MapTypeTwistedTreeline: 10,
+		ionia.MapTypeHowlingAbyss:    12,
+	}
+)
+
+// TournamentSimulator is a stateful, in-memory implementation of the Tournament-Stub-V3
+// and Tournament-V3 endpoints. Providers, tournaments and codes are validated as
+// they are by the Riot API, and tests drive lobbies through JoinLobby, StartGame and
+// CompleteGame, the last of which posts the game result to the provider's callback URL.
+//
+// A simulator can be served on its own with httptest.NewServer, or attached to a
+// Server through its Tournaments field. Unlike the Riot API, callback URLs may use
+// any port, so that they can point at an httptest server.
+type TournamentSimulator struct {
+	// Client is used to post game results to provider callback URLs.
+	// Default: http.DefaultClient.
+	Client *http.Client
+
+	// Now returns the current time, and is used to timestamp lobby events and games.
+	// Default: time.Now.
+	Now func() time.Time
+
+	mu          sync.Mutex
+	nextID      int
+	providers   map[int]*ionia.ProviderRegistration
+	tournaments map[int]*ionia.TournamentRegistration
+	codes       map[string]*simulatedCode
+}
+
+// simulatedCode is the state of a single tournament code.
+type simulatedCode struct {
+	dto       ionia.TournamentCodeDTO
+	events    []ionia.LobbyEventDTO
+	players   []int64
+	startTime time.Time
+	inGame    bool
+	gameIDs   []int64
+}
+
+// NewTournamentSimulator creates a simulator with no providers, tournaments or codes.
+func NewTournamentSimulator() *TournamentSimulator {
+	return &TournamentSimulator{
+		Client:      http.DefaultClient,
+		Now:         time.Now,
+		providers:   make(map[int]*ionia.ProviderRegistration),
+		tournaments: make(map[int]*ionia.TournamentRegistration),
+		codes:       make(map[string]*simulatedCode),
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (t *TournamentSimulator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h := t.handler(r.Method, r.URL.Path)
+	if h == nil {
+		writeStatus(w, http.StatusNotFound)
+		return
+	}
+	h(w, r)
+}
+
+// handler returns the handler for a request, or nil if the simulator does not serve it.
+func (t *TournamentSimulator) handler(method, path string) http.HandlerFunc {
+	const matchIDsPrefix = "/lol/match/v3/matches/by-tournament-code/"
+	if method == http.MethodGet && strings.HasPrefix(path, matchIDsPrefix) && strings.HasSuffix(path, "/ids") {
+		code := strings.TrimSuffix(strings.TrimPrefix(path, matchIDsPrefix), "/ids")
+		return func(w http.ResponseWriter, r *http.Request) { t.matchIDs(w, code) }
+	}
+
+	var rest string
+	switch {
+	case strings.HasPrefix(path, "/lol/tournament-stub/v3/"):
+		rest = strings.TrimPrefix(path, "/lol/tournament-stub/v3/")
+	case strings.HasPrefix(path, "/lol/tournament/v3/"):
+		rest = strings.TrimPrefix(path, "/lol/tournament/v3/")
+	default:
+		return nil
+	}
+
+	segments := strings.Split(rest, "/")
+	switch {
+	case method == http.MethodPost && rest == "providers":
+		return t.registerProvider
+	case method == http.MethodPost && rest == "tournaments":
+		return t.registerTournament
+	case method == http.MethodPost && rest == "codes":
+		return t.createCodes
+	case method == http.MethodGet && len(segments) == 3 && segments[0] == "lobby-events" && segments[1] == "by-code":
+		return func(w http.ResponseWriter, r *http.Request) { t.lobbyEvents(w, segments[2]) }
+	case method == http.MethodGet && len(segments) == 2 && segments[0] == "codes":
+		return func(w http.ResponseWriter, r *http.Request) { t.code(w, segments[1]) }
+	case method == http.MethodPut && len(segments) == 2 && segments[0] == "codes":
+		return func(w http.ResponseWriter, r *http.Request) { t.updateCode(w, r, segments[1]) }
+	}
+	return nil
+}
+
+func (t *TournamentSimulator) registerProvider(w http.ResponseWriter, r *http.Request) {
+	pr := &ionia.ProviderRegistration{}
+	if err := json.NewDecoder(r.Body).Decode(pr); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid provider registration: "+err.Error())
+		return
+	}
+	if _, ok := tournamentRegions[pr.Region]; !ok {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("illegal region %q", pr.Region))
+		return
+	}
+	if u, err := url.Parse(pr.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("illegal callback URL %q", pr.URL))
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.nextID++
+	t.providers[t.nextID] = pr
+	writeJSON(w, t.nextID)
+}
+
+func (t *TournamentSimulator) registerTournament(w http.ResponseWriter, r *http.Request) {
+	tr := &ionia.TournamentRegistration{}
+	if err := json.NewDecoder(r.Body).Decode(tr); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid tournament registration: "+err.Error())
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.providers[tr.ProviderID]; !ok {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown provider %d", tr.ProviderID))
+		return
+	}
+	t.nextID++
+	t.tournaments[t.nextID] = tr
+	writeJSON(w, t.nextID)
+}
+
+func (t *TournamentSimulator) createCodes(w http.ResponseWriter, r *http.Request) {
+	tc := &ionia.TournamentCode{}
+	if err := json.NewDecoder(r.Body).Decode(tc); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid tournament code: "+err.Error())
+		return
+	}
+	if err := tc.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// The client always sends a count, so zero is treated as the default of one.
+	count := 1
+	if v := r.URL.Query().Get("count"); v != "" && v != "0" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > ionia.MaxTournamentCodes {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("count must be between 1 and %d, got %q", ionia.MaxTournamentCodes, v))
+			return
+		}
+		count = n
+	}
+	tournamentID, _ := strconv.Atoi(r.URL.Query().Get("tournamentId"))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tr, ok := t.tournaments[tournamentID]
+	if !ok {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown tournament %d", tournamentID))
+		return
+	}
+	pr := t.providers[tr.ProviderID]
+
+	participants := make([]int64, len(tc.AllowedSummonerIDs))
+	for i, id := range tc.AllowedSummonerIDs {
+		participants[i] = int64(id)
+	}
+
+	t.nextID++
+	codes := make([]string, count)
+	for i := range codes {
+		code := fmt.Sprintf("%s%04d-%d-%d", pr.Region, tournamentID, t.nextID, i)
+		codes[i] = code
+		t.codes[code] = &simulatedCode{dto: ionia.TournamentCodeDTO{
+			Map:          string(tc.MapType),
+			Code:         code,
+			Spectators:   string(tc.SpectatorType),
+			Region:       pr.Region,
+			ProviderID:   tr.ProviderID,
+			TeamSize:     tc.TeamSize,
+			Participants: participants,
+			PickType:     string(tc.PickType),
+			TournamentID: tournamentID,
+			LobbyName:    code,
+			Password:     strconv.Itoa(t.nextID*1000 + i),
+			ID:           t.nextID*1000 + i,
+			Metadata:     tc.Metadata,
+		}}
+	}
+	writeJSON(w, codes)
+}
+
+func (t *TournamentSimulator) code(w http.ResponseWriter, code string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	sc, ok := t.codes[code]
+	if !ok {
+		writeStatus(w, http.StatusNotFound)
+		return
+	}
+	writeJSON(w, sc.dto)
+}
+
+func (t *TournamentSimulator) updateCode(w http.ResponseWriter, r *http.Request, code string) {
+	tcu := &ionia.TournamentCodeUpdate{}
+	if err := json.NewDecoder(r.Body).Decode(tcu); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid tournament code update: "+err.Error())
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	sc, ok := t.codes[code]
+	if !ok {
+		writeStatus(w, http.StatusNotFound)
+		return
+	}
+
+	// Empty fields are left unchanged, so validate the code as it will be after the update.
+	tc := &ionia.TournamentCode{
+		AllowedSummonerIDs: tcu.AllowedSummonerIDs,
+		MapType:            ionia.MapType(sc.dto.Map),
+		PickType:           ionia.PickType(sc.dto.PickType),
+		SpectatorType:      ionia.SpectatorType(sc.dto.Spectators),
+		TeamSize:           sc.dto.TeamSize,
+	}
+	if tcu.MapType != "" {
+		tc.MapType = tcu.MapType
+	}
+	if tcu.PickType != "" {
+		tc.PickType = tcu.PickType
+	}
+	if tcu.SpectatorType != "" {
+		tc.SpectatorType = tcu.SpectatorType
+	}
+	if err := tc.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sc.dto.Map, sc.dto.PickType, sc.dto.Spectators = string(tc.MapType), string(tc.PickType), string(tc.SpectatorType)
+	if tcu.AllowedSummonerIDs != nil {
+		sc.dto.Participants = make([]int64, len(tcu.AllowedSummonerIDs))
+		for i, id := range tcu.AllowedSummonerIDs {
+			sc.dto.Participants[i] = int64(id)
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (t *TournamentSimulator) lobbyEvents(w http.ResponseWriter, code string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	sc, ok := t.codes[code]
+	if !ok {
+		writeStatus(w, http.StatusNotFound)
+		return
+	}
+	writeJSON(w, &ionia.LobbyEventDTOWrapper{EventList: append([]ionia.LobbyEventDTO{}, sc.events...)})
+}
+
+func (t *TournamentSimulator) matchIDs(w http.ResponseWriter, code string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	sc, ok := t.codes[code]
+	if !ok || len(sc.gameIDs) == 0 {
+		writeStatus(w, http.StatusNotFound)
+		return
+	}
+	writeJSON(w, sc.gameIDs)
+}
+
+// JoinLobby adds a summoner to the lobby of a tournament code, creating the lobby
+// if the summoner is the first to join.
+func (t *TournamentSimulator) JoinLobby(code string, summonerID int64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	sc, ok := t.codes[code]
+	if !ok {
+		return fmt.Errorf("unknown tournament code %q", code)
+	}
+	if sc.inGame {
+		return fmt.Errorf("tournament code %q is in game", code)
+	}
+	if len(sc.dto.Participants) > 0 && !containsSummoner(sc.dto.Participants, summonerID) {
+		return fmt.Errorf("summoner %d is not allowed to join %q", summonerID, code)
+	}
+	if containsSummoner(sc.players, summonerID) {
+		return fmt.Errorf("summoner %d has already joined %q", summonerID, code)
+	}
+	if len(sc.players) >= 2*sc.dto.TeamSize {
+		return fmt.Errorf("lobby for %q is full", code)
+	}
+
+	if len(sc.players) == 0 {
+		t.addEvent(sc, "PracticeGameCreatedEvent", summonerID)
+	}
+	sc.players = append(sc.players, summonerID)
+	t.addEvent(sc, "PlayerJoinedGameEvent", summonerID)
+	return nil
+}
+
+// LeaveLobby removes a summoner from the lobby of a tournament code.
+func (t *TournamentSimulator) LeaveLobby(code string, summonerID int64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	sc, ok := t.codes[code]
+	if !ok {
+		return fmt.Errorf("unknown tournament code %q", code)
+	}
+	if sc.inGame {
+		return fmt.Errorf("tournament code %q is in game", code)
+	}
+	for i, id := range sc.players {
+		if id == summonerID {
+			sc.players = append(sc.players[:i], sc.players[i+1:]...)
+			t.addEvent(sc, "PlayerQuitGameEvent", summonerID)
+			return nil
+		}
+	}
+	return fmt.Errorf("summoner %d is not in the lobby for %q", summonerID, code)
+}
+
+// StartGame moves the lobby of a tournament code through champion select and into a game.
+func (t *TournamentSimulator) StartGame(code string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	sc, ok := t.codes[code]
+	if !ok {
+		return fmt.Errorf("unknown tournament code %q", code)
+	}
+	if sc.inGame {
+		return fmt.Errorf("tournament code %q is already in game", code)
+	}
+	if len(sc.players) == 0 {
+		return fmt.Errorf("lobby for %q is empty", code)
+	}
+
+	t.addEvent(sc, "ChampSelectStartedEvent", 0)
+	t.addEvent(sc, "GameAllocationStartedEvent", 0)
+	t.addEvent(sc, "GameAllocatedToLsmEvent", 0)
+	sc.inGame = true
+	sc.startTime = t.Now()
+	return nil
+}
+
+// CompleteGame ends the game being played with a tournament code, and posts the result to
+// the provider's callback URL. If both teams are empty, the players in the lobby are split
+// into two teams in the order they joined, and the first team wins.
+//
+// The callback is returned even if posting it fails.
+func (t *TournamentSimulator) CompleteGame(code string, winningTeam, losingTeam []ionia.TournamentCallbackPlayer) (*ionia.TournamentCallback, error) {
+	t.mu.Lock()
+	sc, ok := t.codes[code]
+	if !ok {
+		t.mu.Unlock()
+		return nil, fmt.Errorf("unknown tournament code %q", code)
+	}
+	if !sc.inGame {
+		t.mu.Unlock()
+		return nil, fmt.Errorf("tournament code %q is not in game", code)
+	}
+
+	if len(winningTeam) == 0 && len(losingTeam) == 0 {
+		half := (len(sc.players) + 1) / 2
+		for i, id := range sc.players {
+			p := ionia.TournamentCallbackPlayer{SummonerName: "Summoner " + strconv.FormatInt(id, 10), SummonerID: id}
+			if i < half {
+				winningTeam = append(winningTeam, p)
+			} else {
+				losingTeam = append(losingTeam, p)
+			}
+		}
+	}
+
+	t.nextID++
+	gameID := int64(t.nextID)
+	mode := "CLASSIC"
+	if ionia.MapType(sc.dto.Map) == ionia.MapTypeHowlingAbyss {
+		mode = "ARAM"
+	}
+	cb := &ionia.TournamentCallback{
+		StartTime:   sc.startTime.UnixNano() / int64(time.Millisecond),
+		ShortCode:   code,
+		MetaData:    sc.dto.Metadata,
+		GameID:      gameID,
+		GameName:    fmt.Sprintf("%s-%d", code, gameID),
+		GameType:    "Practice",
+		GameMap:     tournamentMaps[ionia.MapType(sc.dto.Map)],
+		GameMode:    mode,
+		Region:      tournamentRegions[sc.dto.Region],
+		WinningTeam: winningTeam,
+		LosingTeam:  losingTeam,
+	}
+
+	sc.inGame = false
+	sc.players = nil
+	sc.gameIDs = append(sc.gameIDs, gameID)
+	callbackURL := t.providers[sc.dto.ProviderID].URL
+	t.mu.Unlock()
+
+	body, err := json.Marshal(cb)
+	if err != nil {
+		return cb, err
+	}
+	resp, err := t.Client.Post(callbackURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return cb, fmt.Errorf("posting tournament callback: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return cb, fmt.Errorf("tournament callback returned %s", resp.Status)
+	}
+	return cb, nil
+}
+
+func (t *TournamentSimulator) addEvent(sc *simulatedCode, eventType string, summonerID int64) {
+	e := ionia.LobbyEventDTO{
+		EventType: eventType,
+		Timestamp: strconv.FormatInt(t.Now().UnixNano()/int64(time.Millisecond), 10),
+	}
+	if summonerID != 0 {
+		e.SummonerID = strconv.FormatInt(summonerID, 10)
+	}
+	sc.events = append(sc.events, e)
+}
+
+func containsSummoner(ids []int64, id int64) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	json.NewEncoder(w).Encode(v)
+}
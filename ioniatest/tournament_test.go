@@ -0,0 +1,198 @@
+package ioniatest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brattonross/ionia"
+)
+
+func TestTournamentSimulator(t *testing.T) {
+	srv, c, teardown := createTestServer()
+	defer teardown()
+
+	sim := NewTournamentSimulator()
+	sim.Now = func() time.Time { return time.Unix(1527000000, 0) }
+	srv.Tournaments = sim
+
+	results := make(chan *ionia.TournamentCallback, 1)
+	callback := httptest.NewServer(ionia.TournamentCallbackHandler(func(r *http.Request, cb *ionia.TournamentCallback) error {
+		results <- cb
+		return nil
+	}))
+	defer callback.Close()
+
+	providerID, _, err := c.TournamentStub.Provider(&ionia.ProviderRegistration{URL: callback.URL, Region: "EUW"})
+	if err != nil {
+		t.Fatalf("TournamentStub.Provider returned error: %v", err)
+	}
+	if _, resp, err := c.TournamentStub.Tournament(&ionia.TournamentRegistration{ProviderID: *providerID + 100}); err == nil || resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for unknown provider, got %v", err)
+	}
+	tournamentID, _, err := c.TournamentStub.Tournament(&ionia.TournamentRegistration{Name: "Finals", ProviderID: *providerID})
+	if err != nil {
+		t.Fatalf("TournamentStub.Tournament returned error: %v", err)
+	}
+
+	withTournament := func(o *ionia.TournamentCodesOptions) { o.TournamentID = int64(*tournamentID) }
+	tc := &ionia.TournamentCode{
+		AllowedSummonerIDs: []int{1, 2},
+		MapType:            ionia.MapTypeHowlingAbyss,
+		Metadata:           "game 1",
+		PickType:           ionia.PickTypeBlindPick,
+		SpectatorType:      ionia.SpectatorTypeAll,
+		TeamSize:           1,
+	}
+
+	// The client validates codes before sending them, so post invalid codes directly.
+	invalid := []struct {
+		name string
+		body string
+	}{
+		{"Team Size", `{"mapType": "SUMMONERS_RIFT", "pickType": "BLIND_PICK", "spectatorType": "ALL", "teamSize": 6}`},
+		{"Map Type", `{"mapType": "CRYSTAL_SCAR", "pickType": "BLIND_PICK", "spectatorType": "ALL", "teamSize": 5}`},
+		{"Pick Type", `{"mapType": "SUMMONERS_RIFT", "pickType": "ONE_FOR_ALL", "spectatorType": "ALL", "teamSize": 5}`},
+		{"Spectator Type", `{"mapType": "SUMMONERS_RIFT", "pickType": "BLIND_PICK", "spectatorType": "SOME", "teamSize": 5}`},
+		{"Allowed Summoners", `{"mapType": "SUMMONERS_RIFT", "pickType": "BLIND_PICK", "spectatorType": "ALL", "teamSize": 1, "allowedSummonerIds": [1, 2, 3]}`},
+	}
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			u := fmt.Sprintf("/lol/tournament-stub/v3/codes?tournamentId=%d", *tournamentID)
+			sim.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, u, strings.NewReader(tc.body)))
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("expected 400, got %d", rec.Code)
+			}
+		})
+	}
+
+	codes, _, err := c.TournamentStub.Codes(tc, withTournament)
+	if err != nil {
+		t.Fatalf("TournamentStub.Codes returned error: %v", err)
+	}
+	if len(codes) != 1 {
+		t.Fatalf("expected 1 code, got %d", len(codes))
+	}
+	code := codes[0]
+
+	if err := sim.JoinLobby(code, 3); err == nil {
+		t.Error("expected error joining with a summoner who is not allowed")
+	}
+	for _, id := range []int64{1, 2} {
+		if err := sim.JoinLobby(code, id); err != nil {
+			t.Fatalf("JoinLobby(%d) returned error: %v", id, err)
+		}
+	}
+	if _, err := sim.CompleteGame(code, nil, nil); err == nil {
+		t.Error("expected error completing a game which has not started")
+	}
+	if err := sim.StartGame(code); err != nil {
+		t.Fatalf("StartGame returned error: %v", err)
+	}
+
+	events, _, err := c.TournamentStub.LobbyEventsByCode(code)
+	if err != nil {
+		t.Fatalf("TournamentStub.LobbyEventsByCode returned error: %v", err)
+	}
+	var eventTypes []string
+	for _, e := range events.EventList {
+		eventTypes = append(eventTypes, e.EventType)
+	}
+	wantEventTypes := []string{
+		"PracticeGameCreatedEvent",
+		"PlayerJoinedGameEvent",
+		"PlayerJoinedGameEvent",
+		"ChampSelectStartedEvent",
+		"GameAllocationStartedEvent",
+		"GameAllocatedToLsmEvent",
+	}
+	if !reflect.DeepEqual(eventTypes, wantEventTypes) {
+		t.Errorf("unexpected lobby events: got %v, want %v", eventTypes, wantEventTypes)
+	}
+
+	cb, err := sim.CompleteGame(code, nil, nil)
+	if err != nil {
+		t.Fatalf("CompleteGame returned error: %v", err)
+	}
+	want := &ionia.TournamentCallback{
+		StartTime:   1527000000000,
+		ShortCode:   code,
+		MetaData:    "game 1",
+		GameID:      cb.GameID,
+		GameName:    cb.GameName,
+		GameType:    "Practice",
+		GameMap:     12,
+		GameMode:    "ARAM",
+		Region:      "EUW1",
+		WinningTeam: []ionia.TournamentCallbackPlayer{{SummonerName: "Summoner 1", SummonerID: 1}},
+		LosingTeam:  []ionia.TournamentCallbackPlayer{{SummonerName: "Summoner 2", SummonerID: 2}},
+	}
+	if got := <-results; !reflect.DeepEqual(got, want) {
+		t.Errorf("provider received %+v, want %+v", got, want)
+	}
+
+	ids, _, err := c.Match.MatchIDsByTournamentCode(code)
+	if err != nil {
+		t.Fatalf("Match.MatchIDsByTournamentCode returned error: %v", err)
+	}
+	if !reflect.DeepEqual(ids, []int64{cb.GameID}) {
+		t.Errorf("unexpected match IDs: got %v, want [%d]", ids, cb.GameID)
+	}
+}
+
+func TestTournamentSimulatorUpdateCode(t *testing.T) {
+	sim := NewTournamentSimulator()
+	ts := httptest.NewServer(sim)
+	defer ts.Close()
+	c := NewClient(ts)
+
+	providerID, _, err := c.Tournament.Provider(&ionia.ProviderRegistration{URL: "http://example.com/callback", Region: "NA"})
+	if err != nil {
+		t.Fatalf("Tournament.Provider returned error: %v", err)
+	}
+	tournamentID, _, err := c.Tournament.Tournament(&ionia.TournamentRegistration{ProviderID: *providerID})
+	if err != nil {
+		t.Fatalf("Tournament.Tournament returned error: %v", err)
+	}
+	codes, _, err := c.Tournament.Codes(&ionia.TournamentCode{
+		MapType:       ionia.MapTypeSummonersRift,
+		PickType:      ionia.PickTypeTournamentDraft,
+		SpectatorType: ionia.SpectatorTypeNone,
+		TeamSize:      5,
+	}, func(o *ionia.TournamentCodesOptions) {
+		o.TournamentID = int64(*tournamentID)
+		o.Count = 3
+	})
+	if err != nil {
+		t.Fatalf("Tournament.Codes returned error: %v", err)
+	}
+	if len(codes) != 3 {
+		t.Fatalf("expected 3 codes, got %d", len(codes))
+	}
+
+	rec := httptest.NewRecorder()
+	sim.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/lol/tournament/v3/codes/"+codes[0], strings.NewReader(`{"pickType": "RANDOM"}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for illegal pick type, got %d", rec.Code)
+	}
+	if _, err := c.Tournament.UpdateTournament(codes[0], &ionia.TournamentCodeUpdate{SpectatorType: ionia.SpectatorTypeLobbyOnly, AllowedSummonerIDs: []int{7}}); err != nil {
+		t.Fatalf("Tournament.UpdateTournament returned error: %v", err)
+	}
+
+	dto, _, err := c.Tournament.TournamentCode(codes[0])
+	if err != nil {
+		t.Fatalf("Tournament.TournamentCode returned error: %v", err)
+	}
+	if dto.Spectators != "LOBBYONLY" || dto.PickType != "TOURNAMENT_DRAFT" || !reflect.DeepEqual(dto.Participants, []int64{7}) {
+		t.Errorf("unexpected tournament code after update: %+v", dto)
+	}
+
+	if _, resp, err := c.Tournament.TournamentCode("UNKNOWN"); err == nil || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown code, got %v", err)
+	}
+}
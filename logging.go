@@ -0,0 +1,166 @@
+package ionia
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// redactedValue replaces the API key wherever it would otherwise be logged.
+const redactedValue = "[REDACTED]"
+
+// Logger is the structured logger used by the Client. Arguments are alternating
+// keys and values, as in log/slog, and *slog.Logger satisfies this interface.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// WithLogger returns a ClientOption which logs each request made by the Client.
+// Successful requests are logged at debug level, error responses and requests
+// denied by the Client's rate limiter at warn level, and transport failures at
+// error level. The API key is never logged.
+func WithLogger(l Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// logDenied logs a request which was not sent because a rate limit window was full.
+func (c *Client) logDenied(req *http.Request, keyID, method, retryAfter string) {
+	if c.logger == nil {
+		return
+	}
+	platform := platformOf(req.URL)
+	c.rateMu.Lock()
+	appRate := c.rateLimits[limitKey(keyID, platform, appRateMethod)]
+	methodRate := c.rateLimits[limitKey(keyID, platform, method)]
+	c.rateMu.Unlock()
+
+	c.logger.Warn("riot api request denied by rate limit",
+		"method", method,
+		"platform", platform,
+		"api_key", keyID,
+		"url", c.redact(req.URL.String()),
+		"retry_after", retryAfter,
+		"app_rate_count", formatCounts(&appRate),
+		"method_rate_count", formatCounts(&methodRate),
+		"method_rate_limit", formatLimits(&methodRate),
+	)
+}
+
+// logCall logs the outcome of a request sent by Do.
+func (c *Client) logCall(call *Call, resp *http.Response, err error, latency time.Duration) {
+	if c.logger == nil {
+		return
+	}
+
+	args := []interface{}{
+		"method", call.Method,
+		"platform", platformOf(call.Request.URL),
+		"url", c.redact(call.Request.URL.String()),
+		"latency", latency,
+	}
+	if err != nil {
+		args = append(args, "error", c.redact(err.Error()))
+		c.logger.Error("riot api request failed", args...)
+		return
+	}
+
+	args = append(args, "status", resp.StatusCode)
+	if call.AppRate != nil {
+		args = append(args, "app_rate_count", formatCounts(call.AppRate))
+	}
+	if call.MethodRate != nil {
+		args = append(args, "method_rate_count", formatCounts(call.MethodRate))
+	}
+	if resp.StatusCode == http.StatusOK {
+		c.logger.Debug("riot api request", args...)
+		return
+	}
+
+	if rateType := resp.Header.Get(headerRateLimitType); rateType != "" {
+		args = append(args, "rate_limit_type", rateType)
+	}
+	if retryAfter := resp.Header.Get(headerRetryAfter); retryAfter != "" {
+		args = append(args, "retry_after", retryAfter)
+	}
+	c.logger.Warn("riot api request returned error", args...)
+}
+
+// logRetry logs that a request which failed will be sent again after delay.
+func (c *Client) logRetry(req *http.Request, method string, attempt int, delay time.Duration, resp *http.Response, err error) {
+	if c.logger == nil {
+		return
+	}
+
+	args := []interface{}{
+		"method", method,
+		"platform", platformOf(req.URL),
+		"url", c.redact(req.URL.String()),
+		"attempt", attempt,
+		"delay", delay,
+	}
+	if err != nil {
+		args = append(args, "error", c.redact(err.Error()))
+	} else {
+		args = append(args, "status", resp.StatusCode)
+	}
+	c.logger.Warn("retrying riot api request", args...)
+}
+
+// redact replaces the Client's API keys in s.
+func (c *Client) redact(s string) string {
+	for _, k := range c.apiKeys() {
+		if k.key != "" {
+			s = strings.Replace(s, k.key, redactedValue, -1)
+		}
+	}
+	return s
+}
+
+// platformOf returns the platform a request URL is sent to (e.g. na1 for
+// https://na1.api.riotgames.com/), or the URL's host for other servers.
+func platformOf(u *url.URL) string {
+	host := u.Hostname()
+	if strings.HasSuffix(host, ".api.riotgames.com") {
+		return strings.TrimSuffix(host, ".api.riotgames.com")
+	}
+	return host
+}
+
+// formatCounts formats the counts of r as in the X-*-Rate-Limit-Count headers.
+func formatCounts(r *Rate) string {
+	values := make(map[int]int, len(r.Counts))
+	for s, c := range r.Counts {
+		values[s] = c.Used
+	}
+	return formatRateValues(values)
+}
+
+// formatLimits formats the limits of r as in the X-*-Rate-Limit headers.
+func formatLimits(r *Rate) string {
+	values := make(map[int]int, len(r.Limits))
+	for s, l := range r.Limits {
+		values[s] = l.Allowed
+	}
+	return formatRateValues(values)
+}
+
+func formatRateValues(values map[int]int) string {
+	seconds := make([]int, 0, len(values))
+	for s := range values {
+		seconds = append(seconds, s)
+	}
+	sort.Ints(seconds)
+
+	parts := make([]string, len(seconds))
+	for i, s := range seconds {
+		parts[i] = strconv.Itoa(values[s]) + ":" + strconv.Itoa(s)
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,101 @@
+package ionia
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type logEntry struct {
+	level string
+	msg   string
+	args  map[string]interface{}
+}
+
+type testLogger struct {
+	entries []logEntry
+}
+
+func (l *testLogger) log(level, msg string, args []interface{}) {
+	e := logEntry{level: level, msg: msg, args: make(map[string]interface{})}
+	for i := 0; i+1 < len(args); i += 2 {
+		e.args[args[i].(string)] = args[i+1]
+	}
+	l.entries = append(l.entries, e)
+}
+
+func (l *testLogger) Debug(msg string, args ...interface{}) { l.log("debug", msg, args) }
+func (l *testLogger) Warn(msg string, args ...interface{})  { l.log("warn", msg, args) }
+func (l *testLogger) Error(msg string, args ...interface{}) { l.log("error", msg, args) }
+
+func TestLogging(t *testing.T) {
+	client, mux, _, teardown := createTestServer()
+	defer teardown()
+
+	const apiKey = "RGAPI-secret"
+	client.SetAPIKeys(apiKey)
+	logger := &testLogger{}
+	WithLogger(logger)(client)
+
+	mux.HandleFunc("/lol/summoner/v3/summoners/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(headerAppRateLimit, "20:1,100:120")
+		w.Header().Set(headerAppRateLimitCount, "1:1,1:120")
+		fmt.Fprint(w, `{"id": 1}`)
+	})
+	mux.HandleFunc("/lol/summoner/v3/summoners/2", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(headerMethodRateLimit, "1:10")
+		w.Header().Set(headerMethodRateLimitCount, "1:10")
+		w.Header().Set(headerRateLimitType, "method")
+		w.Header().Set(headerRetryAfter, "7")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	client.Summoner.BySummonerID(1)
+	client.Summoner.BySummonerID(2)
+	client.Summoner.BySummonerID(2)
+
+	WithMiddleware(func(next RoundTripFunc) RoundTripFunc {
+		return func(call *Call) (*http.Response, error) {
+			return nil, errors.New("dial failed for key " + apiKey)
+		}
+	})(client)
+	client.Champion.All()
+
+	want := []struct {
+		level string
+		args  map[string]interface{}
+	}{
+		{"debug", map[string]interface{}{"status": http.StatusOK, "app_rate_count": "1:1,1:120", "platform": "127.0.0.1"}},
+		{"warn", map[string]interface{}{"status": http.StatusTooManyRequests, "rate_limit_type": "method", "retry_after": "7"}},
+		{"warn", map[string]interface{}{"method_rate_count": "1:10", "method_rate_limit": "1:10"}},
+		{"error", map[string]interface{}{"error": "dial failed for key [REDACTED]"}},
+	}
+	if len(logger.entries) != len(want) {
+		t.Fatalf("expected %d log entries, got %d: %+v", len(want), len(logger.entries), logger.entries)
+	}
+	for i, w := range want {
+		e := logger.entries[i]
+		if e.level != w.level {
+			t.Errorf("entry %d: expected level %s, got %s (%s)", i, w.level, e.level, e.msg)
+		}
+		for k, v := range w.args {
+			if e.args[k] != v {
+				t.Errorf("entry %d: expected %s to be %v, got %v", i, k, v, e.args[k])
+			}
+		}
+		for k, v := range e.args {
+			if strings.Contains(fmt.Sprint(v), apiKey) {
+				t.Errorf("entry %d: %s contains the API key", i, k)
+			}
+		}
+	}
+}
+
+func TestPlatformOf(t *testing.T) {
+	c := NewClient("", WithRegion("euw1"))
+	if got := platformOf(c.BaseURL); got != "euw1" {
+		t.Errorf("expected platform euw1, got %s", got)
+	}
+}
@@ -1,6 +1,7 @@
 package ionia
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -393,6 +394,32 @@ func (m *MatchService) MatchTimelineByID(matchID int64) (*MatchTimelineDTO, *htt
 	return mt, resp, nil
 }
 
+// StreamTimelineByID retrieves the timeline of a match, decoding its frames one at a
+// time and passing each to fn instead of holding them all in memory. The returned
+// timeline has no Frames. If fn returns an error, decoding stops and it is returned.
+func (m *MatchService) StreamTimelineByID(matchID int64, fn func(frame *MatchFrameDTO) error) (*MatchTimelineDTO, *http.Response, error) {
+	req, err := m.client.NewRequest(http.MethodGet, "lol/match/v3/timelines/by-match/"+strconv.FormatInt(matchID, 10), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mt := &MatchTimelineDTO{}
+	resp, err := m.client.stream(req, mt, map[string]streamFunc{
+		"frames": func(_ string, dec *json.Decoder) error {
+			frame := &MatchFrameDTO{}
+			if err := dec.Decode(frame); err != nil {
+				return err
+			}
+			return fn(frame)
+		},
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return mt, resp, nil
+}
+
 // MatchIDsByTournamentCode retrieves match IDs for the given tournament code.
 func (m *MatchService) MatchIDsByTournamentCode(tournamentCode string) ([]int64, *http.Response, error) {
 	req, err := m.client.NewRequest(http.MethodGet, fmt.Sprintf("lol/match/v3/matches/by-tournament-code/%s/ids", tournamentCode), nil)
@@ -0,0 +1,288 @@
+package ionia
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MetricsOption is a function which modifies the MetricsOptions.
+type MetricsOption func(*MetricsOptions)
+
+// MetricsOptions specifies the optional parameters for NewMetrics.
+type MetricsOptions struct {
+	// Upper bounds, in seconds, of the request latency histogram buckets.
+	// Default: 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10
+	Buckets []float64
+}
+
+// Metrics collects request counts, latencies, retries and rate limit headroom
+// for the Clients it is attached to with WithMetrics. It implements http.Handler,
+// serving the metrics in the Prometheus text exposition format:
+//
+//	m := ionia.NewMetrics()
+//	c := ionia.NewClient(key, ionia.WithMetrics(m))
+//	http.Handle("/metrics", m)
+type Metrics struct {
+	buckets []float64
+
+	mu        sync.Mutex
+	requests  map[requestKey]uint64
+	latencies map[methodKey]*histogram
+	retries   map[methodKey]uint64
+	windows   map[windowKey]Limit
+	counts    map[windowKey]int
+}
+
+type methodKey struct {
+	platform, method string
+}
+
+type requestKey struct {
+	methodKey
+	status string
+}
+
+type windowKey struct {
+	platform string
+	// The APIKeyID of the key the limits apply to.
+	key string
+	// Either "app" or "method".
+	scope   string
+	method  string
+	seconds int
+}
+
+type histogram struct {
+	// counts[i] is the number of observations in bucket i, not including lower buckets.
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// NewMetrics creates an empty metrics collector.
+func NewMetrics(opts ...MetricsOption) *Metrics {
+	options := &MetricsOptions{
+		Buckets: []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	}
+	for _, o := range opts {
+		o(options)
+	}
+
+	buckets := append([]float64(nil), options.Buckets...)
+	sort.Float64s(buckets)
+	return &Metrics{
+		buckets:   buckets,
+		requests:  make(map[requestKey]uint64),
+		latencies: make(map[methodKey]*histogram),
+		retries:   make(map[methodKey]uint64),
+		windows:   make(map[windowKey]Limit),
+		counts:    make(map[windowKey]int),
+	}
+}
+
+// WithMetrics returns a ClientOption which records the Client's requests in m.
+// Requests are observed by a middleware added after any already given to the Client.
+func WithMetrics(m *Metrics) ClientOption {
+	return func(c *Client) {
+		c.metrics = m
+		c.middleware = append(c.middleware, m.middleware)
+	}
+}
+
+func (m *Metrics) middleware(next RoundTripFunc) RoundTripFunc {
+	return func(call *Call) (*http.Response, error) {
+		start := time.Now()
+		resp, err := next(call)
+		m.observe(call, resp, err, time.Since(start))
+		return resp, err
+	}
+}
+
+// observe records a request, along with the rate limits returned in its response.
+func (m *Metrics) observe(call *Call, resp *http.Response, err error, latency time.Duration) {
+	mk := methodKey{platformOf(call.Request.URL), call.Method}
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[requestKey{mk, status}]++
+
+	h, ok := m.latencies[mk]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		m.latencies[mk] = h
+	}
+	seconds := latency.Seconds()
+	for i, b := range m.buckets {
+		if seconds <= b {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += seconds
+	h.count++
+
+	m.observeRate(mk.platform, call.Key, "app", "", call.AppRate)
+	m.observeRate(mk.platform, call.Key, "method", mk.method, call.MethodRate)
+}
+
+func (m *Metrics) observeRate(platform, key, scope, method string, r *Rate) {
+	if r == nil {
+		return
+	}
+	for s, l := range r.Limits {
+		k := windowKey{platform, key, scope, method, s}
+		m.windows[k] = l
+		m.counts[k] = r.Counts[s].Used
+	}
+}
+
+// observeRetry records that a request for the given method was retried.
+func (m *Metrics) observeRetry(req *http.Request, method string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries[methodKey{platformOf(req.URL), method}]++
+}
+
+// ServeHTTP implements http.Handler.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+// WriteTo writes the metrics to w in the Prometheus text exposition format.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b bytes.Buffer
+	writeHeader(&b, "ionia_requests_total", "counter", "Requests sent to the Riot API, by response status.")
+	requests := make([]requestKey, 0, len(m.requests))
+	for k := range m.requests {
+		requests = append(requests, k)
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		if requests[i].methodKey != requests[j].methodKey {
+			return lessMethodKey(requests[i].methodKey, requests[j].methodKey)
+		}
+		return requests[i].status < requests[j].status
+	})
+	for _, k := range requests {
+		writeSample(&b, "ionia_requests_total", labels("platform", k.platform, "method", k.method, "status", k.status), float64(m.requests[k]))
+	}
+
+	writeHeader(&b, "ionia_request_duration_seconds", "histogram", "Latency of requests sent to the Riot API.")
+	latencies := make([]methodKey, 0, len(m.latencies))
+	for k := range m.latencies {
+		latencies = append(latencies, k)
+	}
+	sortMethodKeys(latencies)
+	for _, k := range latencies {
+		h := m.latencies[k]
+		var cumulative uint64
+		for i, bound := range m.buckets {
+			cumulative += h.counts[i]
+			writeSample(&b, "ionia_request_duration_seconds_bucket", labels("platform", k.platform, "method", k.method, "le", formatFloat(bound)), float64(cumulative))
+		}
+		writeSample(&b, "ionia_request_duration_seconds_bucket", labels("platform", k.platform, "method", k.method, "le", "+Inf"), float64(h.count))
+		writeSample(&b, "ionia_request_duration_seconds_sum", labels("platform", k.platform, "method", k.method), h.sum)
+		writeSample(&b, "ionia_request_duration_seconds_count", labels("platform", k.platform, "method", k.method), float64(h.count))
+	}
+
+	writeHeader(&b, "ionia_retries_total", "counter", "Requests to the Riot API which were retried.")
+	retries := make([]methodKey, 0, len(m.retries))
+	for k := range m.retries {
+		retries = append(retries, k)
+	}
+	sortMethodKeys(retries)
+	for _, k := range retries {
+		writeSample(&b, "ionia_retries_total", labels("platform", k.platform, "method", k.method), float64(m.retries[k]))
+	}
+
+	windows := make([]windowKey, 0, len(m.windows))
+	for k := range m.windows {
+		windows = append(windows, k)
+	}
+	sort.Slice(windows, func(i, j int) bool {
+		a, b := windows[i], windows[j]
+		if a.platform != b.platform {
+			return a.platform < b.platform
+		}
+		if a.key != b.key {
+			return a.key < b.key
+		}
+		if a.scope != b.scope {
+			return a.scope < b.scope
+		}
+		if a.method != b.method {
+			return a.method < b.method
+		}
+		return a.seconds < b.seconds
+	})
+	writeHeader(&b, "ionia_rate_limit_allowed", "gauge", "Requests allowed in each rate limit window, as last reported by the Riot API.")
+	for _, k := range windows {
+		writeSample(&b, "ionia_rate_limit_allowed", windowLabels(k), float64(m.windows[k].Allowed))
+	}
+	writeHeader(&b, "ionia_rate_limit_remaining", "gauge", "Requests remaining in each rate limit window, as last reported by the Riot API.")
+	for _, k := range windows {
+		writeSample(&b, "ionia_rate_limit_remaining", windowLabels(k), float64(m.windows[k].Allowed-m.counts[k]))
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func sortMethodKeys(keys []methodKey) {
+	sort.Slice(keys, func(i, j int) bool { return lessMethodKey(keys[i], keys[j]) })
+}
+
+func lessMethodKey(a, b methodKey) bool {
+	if a.platform != b.platform {
+		return a.platform < b.platform
+	}
+	return a.method < b.method
+}
+
+func windowLabels(k windowKey) string {
+	if k.scope == "app" {
+		return labels("platform", k.platform, "key", k.key, "scope", k.scope, "window", strconv.Itoa(k.seconds))
+	}
+	return labels("platform", k.platform, "key", k.key, "scope", k.scope, "method", k.method, "window", strconv.Itoa(k.seconds))
+}
+
+func writeHeader(b *bytes.Buffer, name, typ, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+func writeSample(b *bytes.Buffer, name, labels string, v float64) {
+	fmt.Fprintf(b, "%s{%s} %s\n", name, labels, formatFloat(v))
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// labels formats alternating label names and values.
+func labels(kv ...string) string {
+	parts := make([]string, 0, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		parts = append(parts, kv[i]+`="`+labelEscaper.Replace(kv[i+1])+`"`)
+	}
+	return strings.Join(parts, ",")
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
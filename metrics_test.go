@@ -0,0 +1,69 @@
+package ionia
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	client, mux, _, teardown := createTestServer()
+	defer teardown()
+
+	mux.HandleFunc("/lol/summoner/v3/summoners/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(headerAppRateLimit, "20:1,100:120")
+		w.Header().Set(headerAppRateLimitCount, "5:1,40:120")
+		w.Header().Set(headerMethodRateLimit, "2000:60")
+		w.Header().Set(headerMethodRateLimitCount, "1:60")
+		fmt.Fprint(w, `{"id": 1}`)
+	})
+	mux.HandleFunc("/lol/summoner/v3/summoners/by-name/nobody", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	m := NewMetrics(func(o *MetricsOptions) { o.Buckets = []float64{60, 1} })
+	WithMetrics(m)(client)
+
+	client.Summoner.BySummonerID(1)
+	client.Summoner.BySummonerID(1)
+	client.Summoner.BySummonerName("nobody")
+	req, _ := client.NewRequest(http.MethodGet, "lol/summoner/v3/summoners/1", nil)
+	m.observeRetry(req, "GET_getBySummonerId")
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("unexpected content type: %s", ct)
+	}
+
+	body := rec.Body.String()
+	key := APIKeyID("")
+	want := []string{
+		"# TYPE ionia_requests_total counter",
+		`ionia_requests_total{platform="127.0.0.1",method="GET_getBySummonerId",status="200"} 2`,
+		`ionia_requests_total{platform="127.0.0.1",method="GET_getBySummonerName",status="404"} 1`,
+		"# TYPE ionia_request_duration_seconds histogram",
+		`ionia_request_duration_seconds_bucket{platform="127.0.0.1",method="GET_getBySummonerId",le="1"} 2`,
+		`ionia_request_duration_seconds_bucket{platform="127.0.0.1",method="GET_getBySummonerId",le="60"} 2`,
+		`ionia_request_duration_seconds_bucket{platform="127.0.0.1",method="GET_getBySummonerId",le="+Inf"} 2`,
+		`ionia_request_duration_seconds_count{platform="127.0.0.1",method="GET_getBySummonerId"} 2`,
+		`ionia_retries_total{platform="127.0.0.1",method="GET_getBySummonerId"} 1`,
+		`ionia_rate_limit_allowed{platform="127.0.0.1",key="` + key + `",scope="app",window="120"} 100`,
+		`ionia_rate_limit_remaining{platform="127.0.0.1",key="` + key + `",scope="app",window="1"} 15`,
+		`ionia_rate_limit_remaining{platform="127.0.0.1",key="` + key + `",scope="app",window="120"} 60`,
+		`ionia_rate_limit_remaining{platform="127.0.0.1",key="` + key + `",scope="method",method="GET_getBySummonerId",window="60"} 1999`,
+	}
+	for _, line := range want {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("metrics are missing line %q\n%s", line, body)
+		}
+	}
+}
+
+func TestLabels(t *testing.T) {
+	if got, want := labels("a", `x"y\z`, "b", "1\n2"), `a="x\"y\\z",b="1\n2"`; got != want {
+		t.Errorf("unexpected labels: got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,63 @@
+package ionia
+
+import "net/http"
+
+// Call describes a single request made by Client.Do, as seen by middleware.
+type Call struct {
+	Request *http.Request
+
+	// The rate limit method name of the request (e.g. GET_getAllChampions).
+	Method string
+
+	// The APIKeyID of the API key the request is sent with.
+	Key string
+
+	// The application and method rate limits parsed from the response headers.
+	// They are nil until the response has been received from the Riot API, and
+	// stay nil in the middleware chain if a middleware returns a response without
+	// calling next. Once the chain returns, the Client parses any rates still nil
+	// from the response, so the rate limits of a short-circuited response are
+	// tracked like any other.
+	AppRate    *Rate
+	MethodRate *Rate
+}
+
+// RoundTripFunc sends the request of a call and returns its response.
+type RoundTripFunc func(call *Call) (*http.Response, error)
+
+// Middleware wraps the step of Client.Do which sends a request to the Riot API.
+// A middleware may inspect or modify the call before calling next, inspect the
+// response and rate limits afterwards, or return a response without calling next.
+//
+//	logging := func(next ionia.RoundTripFunc) ionia.RoundTripFunc {
+//		return func(call *ionia.Call) (*http.Response, error) {
+//			resp, err := next(call)
+//			log.Printf("%s %v", call.Method, err)
+//			return resp, err
+//		}
+//	}
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// WithMiddleware returns a ClientOption which adds middleware to the Client.
+// The first middleware given is the outermost, and sees each call first.
+func WithMiddleware(mw ...Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mw...)
+	}
+}
+
+// roundTrip sends the call through the Client's middleware and then its http.Client.
+func (c *Client) roundTrip(call *Call) (*http.Response, error) {
+	send := RoundTripFunc(func(call *Call) (*http.Response, error) {
+		resp, err := c.client.Do(call.Request)
+		if err != nil {
+			return nil, err
+		}
+		call.AppRate, call.MethodRate = parseRates(resp)
+		return resp, nil
+	})
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		send = c.middleware[i](send)
+	}
+	return send(call)
+}
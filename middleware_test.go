@@ -0,0 +1,106 @@
+package ionia
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	client, mux, _, teardown := createTestServer()
+	defer teardown()
+
+	mux.HandleFunc("/lol/platform/v3/champions", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Trace-Id"); got != "abc" {
+			t.Errorf("expected X-Trace-Id header to be abc, got %q", got)
+		}
+		w.Header().Set(headerAppRateLimit, "20:1")
+		w.Header().Set(headerAppRateLimitCount, "3:1")
+		fmt.Fprint(w, `{"champions": []}`)
+	})
+
+	var order []string
+	var seen *Call
+	trace := func(next RoundTripFunc) RoundTripFunc {
+		return func(call *Call) (*http.Response, error) {
+			order = append(order, "trace")
+			call.Request.Header.Set("X-Trace-Id", "abc")
+			return next(call)
+		}
+	}
+	record := func(next RoundTripFunc) RoundTripFunc {
+		return func(call *Call) (*http.Response, error) {
+			order = append(order, "record")
+			resp, err := next(call)
+			seen = call
+			return resp, err
+		}
+	}
+	WithMiddleware(trace, record)(client)
+
+	if _, _, err := client.Champion.All(); err != nil {
+		t.Fatalf("Champion.All returned error: %v", err)
+	}
+	if want := []string{"trace", "record"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("unexpected middleware order: got %v, want %v", order, want)
+	}
+	if seen == nil || seen.Method != "GET_getAllChampions" {
+		t.Fatalf("unexpected call: %+v", seen)
+	}
+	if want := (Count{3, 1}); seen.AppRate == nil || seen.AppRate.Counts[1] != want {
+		t.Errorf("unexpected app rate: %+v", seen.AppRate)
+	}
+}
+
+func TestMiddleware_ShortCircuit(t *testing.T) {
+	client, mux, _, teardown := createTestServer()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request sent despite short-circuiting middleware")
+	})
+
+	cached := func(next RoundTripFunc) RoundTripFunc {
+		return func(call *Call) (*http.Response, error) {
+			h := make(http.Header)
+			h.Set(headerMethodRateLimit, "10:10")
+			h.Set(headerMethodRateLimitCount, "10:10")
+			return &http.Response{
+				Status:     "200 OK",
+				StatusCode: http.StatusOK,
+				Header:     h,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"id": 17}`)),
+				Request:    call.Request,
+			}, nil
+		}
+	}
+	var seen *Rate
+	outer := func(next RoundTripFunc) RoundTripFunc {
+		return func(call *Call) (*http.Response, error) {
+			resp, err := next(call)
+			seen = call.MethodRate
+			return resp, err
+		}
+	}
+	WithMiddleware(outer, cached)(client)
+
+	c, _, err := client.Champion.ByID(17)
+	if err != nil {
+		t.Fatalf("Champion.ByID returned error: %v", err)
+	}
+	if c.ID != 17 {
+		t.Errorf("unexpected champion: %+v", c)
+	}
+
+	if seen != nil {
+		t.Errorf("expected no method rate in the middleware chain, got %+v", seen)
+	}
+
+	// Rate limits in a short-circuited response are still tracked.
+	if _, resp, _ := client.Champion.ByID(17); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected method rate limit to be enforced, got status %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,149 @@
+package ionia
+
+import "sort"
+
+// Team IDs as returned by the Match and Spectator APIs.
+const (
+	TeamBlue = 100
+	TeamRed  = 200
+)
+
+// ObjectiveType is the kind of objective taken by a team.
+type ObjectiveType string
+
+// Objective types reported by ObjectiveTimeline.
+const (
+	ObjectiveTower      ObjectiveType = "TOWER"
+	ObjectiveInhibitor  ObjectiveType = "INHIBITOR"
+	ObjectiveDragon     ObjectiveType = "DRAGON"
+	ObjectiveRiftHerald ObjectiveType = "RIFT_HERALD"
+	ObjectiveBaron      ObjectiveType = "BARON_NASHOR"
+)
+
+// Objective is a single objective taken by a team during a match.
+type Objective struct {
+	// Time at which the objective was taken, in milliseconds from the start of the game.
+	Timestamp int64
+
+	Type ObjectiveType
+
+	// The monster sub type (e.g. FIRE_DRAGON, ELDER_DRAGON) for dragons, or the
+	// tower type (e.g. OUTER_TURRET, NEXUS_TURRET) for towers.
+	SubType string
+
+	// The lane of the building (e.g. TOP_LANE, MID_LANE, BOT_LANE) for towers and inhibitors.
+	Lane string
+
+	// The team credited with the objective.
+	TeamID int
+
+	// The participant who took the objective. This is 0 if it was taken by minions.
+	KillerID int
+
+	// The credited team's total gold minus the opposing team's total gold,
+	// as of the latest frame at or before the objective was taken.
+	GoldLead int
+}
+
+// ObjectiveTimeline builds the objectives taken by each team from the BUILDING_KILL
+// and ELITE_MONSTER_KILL events of a match timeline. The result is keyed by team ID,
+// and each team's objectives are ordered by time.
+//
+// The match is used to resolve which team each participant played on.
+func ObjectiveTimeline(m *MatchDTO, mt *MatchTimelineDTO) map[int][]Objective {
+	teams := make(map[int]int)
+	for _, p := range m.Participants {
+		teams[p.ParticipantID] = p.TeamID
+	}
+
+	objectives := make(map[int][]Objective)
+	prev := make(map[int]int)
+	for _, f := range mt.Frames {
+		cur := teamGold(f, teams)
+		for i := range f.Events {
+			o, ok := objectiveFromEvent(&f.Events[i], teams)
+			if !ok {
+				continue
+			}
+
+			gold := prev
+			if o.Timestamp >= f.Timestamp {
+				gold = cur
+			}
+			o.GoldLead = gold[o.TeamID] - gold[opposingTeam(o.TeamID)]
+			objectives[o.TeamID] = append(objectives[o.TeamID], o)
+		}
+		prev = cur
+	}
+
+	for _, os := range objectives {
+		sort.SliceStable(os, func(i, j int) bool {
+			return os[i].Timestamp < os[j].Timestamp
+		})
+	}
+
+	return objectives
+}
+
+func objectiveFromEvent(e *MatchEventDTO, teams map[int]int) (Objective, bool) {
+	o := Objective{
+		Timestamp: e.Timestamp,
+		KillerID:  e.KillerID,
+	}
+
+	switch e.Type {
+	case "BUILDING_KILL":
+		switch e.BuildingType {
+		case "TOWER_BUILDING":
+			o.Type = ObjectiveTower
+			o.SubType = e.TowerType
+		case "INHIBITOR_BUILDING":
+			o.Type = ObjectiveInhibitor
+		default:
+			return o, false
+		}
+		// The event's team ID is the team which owned the building.
+		o.Lane = e.LaneType
+		o.TeamID = opposingTeam(e.TeamID)
+	case "ELITE_MONSTER_KILL":
+		switch e.MonsterType {
+		case "DRAGON":
+			o.Type = ObjectiveDragon
+			o.SubType = e.MonsterSubType
+		case "RIFT_HERALD":
+			o.Type = ObjectiveRiftHerald
+		case "BARON_NASHOR":
+			o.Type = ObjectiveBaron
+		default:
+			return o, false
+		}
+		team, ok := teams[e.KillerID]
+		if !ok {
+			return o, false
+		}
+		o.TeamID = team
+	default:
+		return o, false
+	}
+
+	return o, true
+}
+
+// teamGold sums the total gold of each team's participants in the given frame.
+func teamGold(f MatchFrameDTO, teams map[int]int) map[int]int {
+	gold := make(map[int]int)
+	for id, pf := range f.ParticipantFrames {
+		if pf.ParticipantID != 0 {
+			id = pf.ParticipantID
+		}
+		gold[teams[id]] += pf.TotalGold
+	}
+	return gold
+}
+
+func opposingTeam(teamID int) int {
+	if teamID == TeamBlue {
+		return TeamRed
+	}
+	return TeamBlue
+}
@@ -0,0 +1,78 @@
+package ionia
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestObjectiveTimeline(t *testing.T) {
+	m := &MatchDTO{
+		Participants: []ParticipantDTO{
+			{ParticipantID: 1, TeamID: TeamBlue},
+			{ParticipantID: 2, TeamID: TeamRed},
+		},
+	}
+	mt := &MatchTimelineDTO{
+		Frames: []MatchFrameDTO{
+			{
+				Timestamp: 0,
+				ParticipantFrames: map[int]MatchParticipantFrameDTO{
+					1: {ParticipantID: 1, TotalGold: 500},
+					2: {ParticipantID: 2, TotalGold: 500},
+				},
+			},
+			{
+				Timestamp: 60000,
+				ParticipantFrames: map[int]MatchParticipantFrameDTO{
+					1: {ParticipantID: 1, TotalGold: 1500},
+					2: {ParticipantID: 2, TotalGold: 1000},
+				},
+				Events: []MatchEventDTO{
+					{
+						Type:           "ELITE_MONSTER_KILL",
+						Timestamp:      45000,
+						KillerID:       2,
+						MonsterType:    "DRAGON",
+						MonsterSubType: "FIRE_DRAGON",
+					},
+					{
+						Type:         "BUILDING_KILL",
+						Timestamp:    30000,
+						KillerID:     1,
+						TeamID:       TeamRed,
+						BuildingType: "TOWER_BUILDING",
+						LaneType:     "MID_LANE",
+						TowerType:    "OUTER_TURRET",
+					},
+					{Type: "CHAMPION_KILL", Timestamp: 50000, KillerID: 1, VictimID: 2},
+				},
+			},
+			{
+				Timestamp: 120000,
+				ParticipantFrames: map[int]MatchParticipantFrameDTO{
+					1: {ParticipantID: 1, TotalGold: 2500},
+					2: {ParticipantID: 2, TotalGold: 1500},
+				},
+				Events: []MatchEventDTO{
+					{Type: "ELITE_MONSTER_KILL", Timestamp: 120000, KillerID: 1, MonsterType: "BARON_NASHOR"},
+					{Type: "BUILDING_KILL", Timestamp: 110000, TeamID: TeamRed, BuildingType: "INHIBITOR_BUILDING", LaneType: "BOT_LANE"},
+				},
+			},
+		},
+	}
+
+	got := ObjectiveTimeline(m, mt)
+	want := map[int][]Objective{
+		TeamBlue: {
+			{Timestamp: 30000, Type: ObjectiveTower, SubType: "OUTER_TURRET", Lane: "MID_LANE", TeamID: TeamBlue, KillerID: 1, GoldLead: 0},
+			{Timestamp: 110000, Type: ObjectiveInhibitor, Lane: "BOT_LANE", TeamID: TeamBlue, GoldLead: 500},
+			{Timestamp: 120000, Type: ObjectiveBaron, TeamID: TeamBlue, KillerID: 1, GoldLead: 1000},
+		},
+		TeamRed: {
+			{Timestamp: 45000, Type: ObjectiveDragon, SubType: "FIRE_DRAGON", TeamID: TeamRed, KillerID: 2, GoldLead: 0},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ObjectiveTimeline = %+v, want %+v", got, want)
+	}
+}
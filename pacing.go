@@ -0,0 +1,143 @@
+package ionia
+
+import (
+	"sync"
+	"time"
+)
+
+// PacingOption is a function which modifies the PacingOptions.
+type PacingOption func(*PacingOptions)
+
+// PacingOptions specifies the optional parameters for WithPacing.
+type PacingOptions struct {
+	// The application rate limits assumed for a platform until the Riot API has
+	// reported them, in the format of the X-App-Rate-Limit header.
+	// Default: "20:1,100:120", the limits of a development key.
+	AppLimits string
+
+	// The method rate limits assumed for each method until the Riot API has
+	// reported them. Default: "", so methods are not paced until their limits are known.
+	MethodLimits string
+}
+
+// WithPacing returns a ClientOption which spreads the Client's requests evenly
+// across the tightest window of their rate limits, instead of sending them as fast
+// as possible until a window is full. For example, with limits of 100:120 requests
+// are sent at most every 1.2 seconds.
+//
+// Paced requests wait in the Client's Scheduler, which is created if it has none,
+// so requests wait for their turn rather than being denied.
+//
+// Pacing is per Client: the time of the last request is kept in memory, not in
+// the RateLimitStore. Clients sharing a store, in one process or several, each
+// pace at the full interval, so together they send requests that much more
+// often. The shared store still keeps them within the rate limits.
+func WithPacing(opts ...PacingOption) ClientOption {
+	options := &PacingOptions{
+		AppLimits: "20:1,100:120",
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return func(c *Client) {
+		c.pacing = options
+	}
+}
+
+// pacedStore is a RateLimitStore which spaces requests for each key by the
+// interval of its tightest window, before taking them from the wrapped store.
+// The time of the last request for each key is only known to this Client.
+type pacedStore struct {
+	RateLimitStore
+
+	appInterval    time.Duration
+	methodInterval time.Duration
+
+	mu        sync.Mutex
+	intervals map[string]time.Duration
+	last      map[string]time.Time
+}
+
+func newPacedStore(s RateLimitStore, o *PacingOptions) *pacedStore {
+	return &pacedStore{
+		RateLimitStore: s,
+		appInterval:    paceInterval(parseLimits(o.AppLimits)),
+		methodInterval: paceInterval(parseLimits(o.MethodLimits)),
+		intervals:      make(map[string]time.Duration),
+		last:           make(map[string]time.Time),
+	}
+}
+
+// Take implements RateLimitStore.
+func (p *pacedStore) Take(keys []string, now time.Time) (time.Duration, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var wait time.Duration
+	for _, key := range keys {
+		last, ok := p.last[key]
+		if !ok {
+			continue
+		}
+		if d := last.Add(p.interval(key)).Sub(now); d > wait {
+			wait = d
+		}
+	}
+	if wait > 0 {
+		return wait, nil
+	}
+
+	wait, err := p.RateLimitStore.Take(keys, now)
+	if err != nil || wait > 0 {
+		return wait, err
+	}
+	for _, key := range keys {
+		p.last[key] = now
+	}
+	return 0, nil
+}
+
+// Update implements RateLimitStore.
+func (p *pacedStore) Update(key string, rate Rate, now time.Time) error {
+	// Responses without rate limit headers, such as some errors, keep the last interval.
+	if len(rate.Limits) > 0 {
+		p.mu.Lock()
+		p.intervals[key] = paceInterval(rate.Limits)
+		p.mu.Unlock()
+	}
+	return p.RateLimitStore.Update(key, rate, now)
+}
+
+// Headroom implements RateLimitPeeker, if the wrapped store does.
+func (p *pacedStore) Headroom(keys []string, now time.Time) (float64, error) {
+	if peeker, ok := p.RateLimitStore.(RateLimitPeeker); ok {
+		return peeker.Headroom(keys, now)
+	}
+	return 1, errNoHeadroom
+}
+
+// interval returns the pacing interval for key. p.mu must be held.
+func (p *pacedStore) interval(key string) time.Duration {
+	if d, ok := p.intervals[key]; ok {
+		return d
+	}
+	if _, _, method := splitLimitKey(key); method == appRateMethod {
+		return p.appInterval
+	}
+	return p.methodInterval
+}
+
+// paceInterval returns the interval between requests which spreads them evenly
+// across the tightest of the limits.
+func paceInterval(limits map[int]Limit) time.Duration {
+	var interval time.Duration
+	for s, l := range limits {
+		if l.Allowed <= 0 {
+			continue
+		}
+		if d := time.Duration(s) * time.Second / time.Duration(l.Allowed); d > interval {
+			interval = d
+		}
+	}
+	return interval
+}
@@ -0,0 +1,78 @@
+package ionia
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestPaceInterval(t *testing.T) {
+	tt := []struct {
+		name   string
+		limits string
+		want   time.Duration
+	}{
+		{"None", "", 0},
+		{"Single", "100:120", 1200 * time.Millisecond},
+		{"Tightest", "20:1,100:120", 1200 * time.Millisecond},
+		{"Short Window", "10:1,1000:600", 600 * time.Millisecond},
+	}
+
+	for _, tc := range tt {
+		if got := paceInterval(parseLimits(tc.limits)); got != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestPacedStore(t *testing.T) {
+	p := newPacedStore(NewMemoryRateLimitStore(), &PacingOptions{AppLimits: "20:1,100:120"})
+	now := time.Unix(1527000000, 0)
+	keys := []string{"na1 app", "na1 GET_getMatch"}
+
+	// Before any headers arrive, the default app limits are assumed.
+	if wait, _ := p.Take(keys, now); wait != 0 {
+		t.Fatalf("first Take waited %v", wait)
+	}
+	if wait, _ := p.Take(keys, now.Add(time.Second)); wait != 200*time.Millisecond {
+		t.Errorf("expected to wait 200ms, got %v", wait)
+	}
+	if wait, _ := p.Take(keys, now.Add(1200*time.Millisecond)); wait != 0 {
+		t.Errorf("expected no wait after the interval, got %v", wait)
+	}
+
+	now = now.Add(1200 * time.Millisecond)
+	p.Update("na1 app", Rate{Limits: parseLimits("500:10"), Counts: parseCounts("2:10")}, now)
+	p.Update("na1 GET_getMatch", Rate{Limits: parseLimits("100:1"), Counts: parseCounts("2:1")}, now)
+	// A response without headers keeps the reported limits.
+	p.Update("na1 app", Rate{}, now)
+	if wait, _ := p.Take(keys, now.Add(20*time.Millisecond)); wait != 0 {
+		t.Errorf("expected no wait with the reported limits, got %v", wait)
+	}
+	if wait, _ := p.Take(keys, now.Add(30*time.Millisecond)); wait != 10*time.Millisecond {
+		t.Errorf("expected to wait 10ms, got %v", wait)
+	}
+}
+
+func TestClientPacing(t *testing.T) {
+	client, mux, _, teardown := createTestServer()
+	defer teardown()
+
+	// Without rate limit headers, the assumed limits are used for every request.
+	mux.HandleFunc("/lol/summoner/v3/summoners/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 1}`)
+	})
+	paced := NewClient("", WithPacing(func(o *PacingOptions) { o.AppLimits = "10:1" }))
+	paced.BaseURL = client.BaseURL
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if _, resp, err := paced.Summoner.BySummonerID(1); err != nil || resp.StatusCode != http.StatusOK {
+			t.Fatalf("Summoner.BySummonerID returned %v, %v", resp, err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond {
+		t.Errorf("expected requests to be paced, took %v", elapsed)
+	}
+}
@@ -0,0 +1,244 @@
+package ionia
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// RateLimitStore counts requests against rate limit windows for the Client's limiter.
+// Sharing a store between Clients, including Clients in other processes, makes
+// them share one budget for the API key.
+//
+// Keys identify a platform and method, or a platform's application limit. An
+// implementation backed by a networked store such as Redis must perform Take
+// atomically, for example with a script, so that concurrent callers cannot both
+// take the last request in a window.
+type RateLimitStore interface {
+	// Take records one request against every window of each key, unless any of
+	// them is full. If a window is full, nothing is recorded and the time until
+	// it resets is returned.
+	Take(keys []string, now time.Time) (wait time.Duration, err error)
+
+	// Update stores the limits and counts reported by the Riot API for key.
+	// Counts are only raised, since requests taken from the store may not yet
+	// have been counted by the Riot API.
+	Update(key string, rate Rate, now time.Time) error
+}
+
+// RateLimitPeeker is implemented by RateLimitStores which can report how much of
+// their budget is left without taking a request. A Client with several API keys
+// uses it to pick the key with the most headroom.
+type RateLimitPeeker interface {
+	// Headroom returns the smallest fraction of requests remaining in any window
+	// of the keys, or 1 if none of them has a window which is in progress.
+	Headroom(keys []string, now time.Time) (float64, error)
+}
+
+var errNoHeadroom = errors.New("rate limit store does not report headroom")
+
+// WithRateLimitStore returns a ClientOption which sets the store used to count requests.
+// Default: a new MemoryRateLimitStore.
+func WithRateLimitStore(s RateLimitStore) ClientOption {
+	return func(c *Client) {
+		c.rateStore = s
+	}
+}
+
+// rateWindow is the state of a single window in a rate limit store.
+type rateWindow struct {
+	Allowed int       `json:"allowed"`
+	Count   int       `json:"count"`
+	Start   time.Time `json:"start"`
+}
+
+// rateWindows holds the windows of each key, keyed by window length in seconds.
+type rateWindows map[string]map[int]*rateWindow
+
+func (rw rateWindows) take(keys []string, now time.Time) time.Duration {
+	var wait time.Duration
+	for _, key := range keys {
+		for s, w := range rw[key] {
+			end := w.Start.Add(time.Duration(s) * time.Second)
+			if !now.Before(end) {
+				w.Start, w.Count = now, 0
+				continue
+			}
+			if w.Count >= w.Allowed && end.Sub(now) > wait {
+				wait = end.Sub(now)
+			}
+		}
+	}
+	if wait > 0 {
+		return wait
+	}
+
+	for _, key := range keys {
+		for _, w := range rw[key] {
+			w.Count++
+		}
+	}
+	return 0
+}
+
+func (rw rateWindows) headroom(keys []string, now time.Time) float64 {
+	headroom := 1.0
+	for _, key := range keys {
+		for s, w := range rw[key] {
+			if w.Allowed <= 0 || !now.Before(w.Start.Add(time.Duration(s)*time.Second)) {
+				continue
+			}
+			h := float64(w.Allowed-w.Count) / float64(w.Allowed)
+			if h < 0 {
+				h = 0
+			}
+			if h < headroom {
+				headroom = h
+			}
+		}
+	}
+	return headroom
+}
+
+func (rw rateWindows) update(key string, rate Rate, now time.Time) {
+	if len(rate.Limits) == 0 {
+		return
+	}
+	old := rw[key]
+	windows := make(map[int]*rateWindow, len(rate.Limits))
+	for s, l := range rate.Limits {
+		used := rate.Counts[s].Used
+		w, ok := old[s]
+		if !ok || !now.Before(w.Start.Add(time.Duration(s)*time.Second)) {
+			w = &rateWindow{Start: now}
+		}
+		w.Allowed = l.Allowed
+		if used > w.Count {
+			w.Count = used
+		}
+		windows[s] = w
+	}
+	rw[key] = windows
+}
+
+// MemoryRateLimitStore is a RateLimitStore which keeps counts in memory.
+// It can be shared by Clients in the same process.
+type MemoryRateLimitStore struct {
+	mu      sync.Mutex
+	windows rateWindows
+}
+
+// NewMemoryRateLimitStore creates an empty in-memory store.
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{windows: make(rateWindows)}
+}
+
+// Take implements RateLimitStore.
+func (m *MemoryRateLimitStore) Take(keys []string, now time.Time) (time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.windows.take(keys, now), nil
+}
+
+// Headroom implements RateLimitPeeker.
+func (m *MemoryRateLimitStore) Headroom(keys []string, now time.Time) (float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.windows.headroom(keys, now), nil
+}
+
+// Update implements RateLimitStore.
+func (m *MemoryRateLimitStore) Update(key string, rate Rate, now time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.windows.update(key, rate, now)
+	return nil
+}
+
+// FileRateLimitStore is a RateLimitStore which keeps counts as JSON in a file,
+// so that processes on the same machine or sharing a filesystem share a budget.
+// Access is serialized by locking a file next to Path.
+type FileRateLimitStore struct {
+	Path string
+
+	// On platforms without flock, such as Windows, a lock file older than this
+	// is assumed to have been left by a crashed process, and is removed.
+	// Default: 10 seconds.
+	StaleLock time.Duration
+}
+
+// Take implements RateLimitStore.
+func (f *FileRateLimitStore) Take(keys []string, now time.Time) (time.Duration, error) {
+	var wait time.Duration
+	err := f.modify(func(rw rateWindows) {
+		wait = rw.take(keys, now)
+	})
+	return wait, err
+}
+
+// Update implements RateLimitStore.
+func (f *FileRateLimitStore) Update(key string, rate Rate, now time.Time) error {
+	return f.modify(func(rw rateWindows) {
+		rw.update(key, rate, now)
+	})
+}
+
+// Headroom implements RateLimitPeeker.
+func (f *FileRateLimitStore) Headroom(keys []string, now time.Time) (float64, error) {
+	unlock, err := f.lock()
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	rw, err := f.load()
+	if err != nil {
+		return 0, err
+	}
+	return rw.headroom(keys, now), nil
+}
+
+// modify applies fn to the stored windows while holding the lock file.
+func (f *FileRateLimitStore) modify(fn func(rateWindows)) error {
+	unlock, err := f.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	rw, err := f.load()
+	if err != nil {
+		return err
+	}
+
+	fn(rw)
+
+	b, err := json.Marshal(rw)
+	if err != nil {
+		return err
+	}
+	tmp := f.Path + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.Path)
+}
+
+// load reads the stored windows. The lock must be held.
+func (f *FileRateLimitStore) load() (rateWindows, error) {
+	rw := make(rateWindows)
+	b, err := ioutil.ReadFile(f.Path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, &rw); err != nil {
+			return nil, fmt.Errorf("reading %s: %v", f.Path, err)
+		}
+	}
+	return rw, nil
+}
@@ -0,0 +1,33 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package ionia
+
+import (
+	"os"
+	"syscall"
+)
+
+// lock takes an exclusive flock on the lock file, waiting for other holders to
+// release it. The lock is released by the OS if its holder crashes, so it is
+// never stale.
+func (f *FileRateLimitStore) lock() (unlock func(), err error) {
+	lf, err := os.OpenFile(f.Path+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		err = syscall.Flock(int(lf.Fd()), syscall.LOCK_EX)
+		if err != syscall.EINTR {
+			break
+		}
+	}
+	if err != nil {
+		lf.Close()
+		return nil, err
+	}
+	return func() {
+		syscall.Flock(int(lf.Fd()), syscall.LOCK_UN)
+		lf.Close()
+	}, nil
+}
@@ -0,0 +1,46 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package ionia
+
+import (
+	"os"
+	"time"
+)
+
+// lock creates the lock file, waiting for other holders to release it.
+func (f *FileRateLimitStore) lock() (unlock func(), err error) {
+	stale := f.StaleLock
+	if stale <= 0 {
+		stale = 10 * time.Second
+	}
+
+	name := f.Path + ".lock"
+	for {
+		lf, err := os.OpenFile(name, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			lf.Close()
+			return func() { os.Remove(name) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		fi, err := os.Stat(name)
+		if err != nil || time.Since(fi.ModTime()) <= stale {
+			time.Sleep(time.Millisecond)
+			continue
+		}
+		// Another process may have found the same lock stale, removed it and
+		// created its own. Move the lock aside first, and only remove it if it
+		// is still the stale one; otherwise put it back.
+		aside := name + ".stale"
+		if os.Rename(name, aside) != nil {
+			continue
+		}
+		if ai, err := os.Stat(aside); err == nil && os.SameFile(fi, ai) {
+			os.Remove(aside)
+			continue
+		}
+		os.Rename(aside, name)
+	}
+}
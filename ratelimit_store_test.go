@@ -0,0 +1,187 @@
+package ionia
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testRate(allowed, used, seconds int) Rate {
+	return Rate{
+		Counts: map[int]Count{seconds: {used, seconds}},
+		Limits: map[int]Limit{seconds: {allowed, seconds}},
+	}
+}
+
+func testRateLimitStore(t *testing.T, s RateLimitStore) {
+	now := time.Unix(1527000000, 0)
+
+	if wait, err := s.Take([]string{"na1 app"}, now); err != nil || wait != 0 {
+		t.Fatalf("Take with no known limits = %v, %v; want 0, nil", wait, err)
+	}
+
+	if err := s.Update("na1 app", testRate(3, 1, 10), now); err != nil {
+		t.Fatalf("Update returned error: %v", err)
+	}
+	if err := s.Update("na1 GET_getMatch", testRate(100, 0, 10), now); err != nil {
+		t.Fatalf("Update returned error: %v", err)
+	}
+	keys := []string{"na1 app", "na1 GET_getMatch"}
+	for i := 0; i < 2; i++ {
+		if wait, err := s.Take(keys, now.Add(time.Second)); err != nil || wait != 0 {
+			t.Fatalf("Take %d = %v, %v; want 0, nil", i, wait, err)
+		}
+	}
+	if wait, err := s.Take(keys, now.Add(time.Second)); err != nil || wait != 9*time.Second {
+		t.Errorf("Take with a full window = %v, %v; want 9s, nil", wait, err)
+	}
+	if p, ok := s.(RateLimitPeeker); ok {
+		if h, err := p.Headroom(keys, now.Add(time.Second)); err != nil || h != 0 {
+			t.Errorf("Headroom with a full window = %v, %v; want 0, nil", h, err)
+		}
+		if h, err := p.Headroom([]string{"na1 GET_getMatch"}, now.Add(time.Second)); err != nil || h != 0.98 {
+			t.Errorf("Headroom = %v, %v; want 0.98, nil", h, err)
+		}
+	}
+
+	// A lower count from the Riot API does not release requests which were taken.
+	if err := s.Update("na1 app", testRate(3, 1, 10), now.Add(2*time.Second)); err != nil {
+		t.Fatalf("Update returned error: %v", err)
+	}
+	if wait, _ := s.Take(keys, now.Add(2*time.Second)); wait != 8*time.Second {
+		t.Errorf("Take after a lower count = %v; want 8s", wait)
+	}
+
+	if wait, err := s.Take(keys, now.Add(10*time.Second)); err != nil || wait != 0 {
+		t.Errorf("Take after the window reset = %v, %v; want 0, nil", wait, err)
+	}
+}
+
+func TestMemoryRateLimitStore(t *testing.T) {
+	testRateLimitStore(t, NewMemoryRateLimitStore())
+}
+
+func TestFileRateLimitStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ionia")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	testRateLimitStore(t, &FileRateLimitStore{Path: filepath.Join(dir, "limits.json")})
+}
+
+func TestFileRateLimitStoreShared(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ionia")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "limits.json")
+	now := time.Now()
+	if err := (&FileRateLimitStore{Path: path}).Update("na1 app", testRate(20, 0, 10), now); err != nil {
+		t.Fatalf("Update returned error: %v", err)
+	}
+	// A lock file left by a crashed process does not block the stores.
+	if err := ioutil.WriteFile(path+".lock", nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := now.Add(-time.Minute)
+	os.Chtimes(path+".lock", old, old)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	taken := 0
+	for i := 0; i < 2; i++ {
+		s := &FileRateLimitStore{Path: path}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 15; j++ {
+				wait, err := s.Take([]string{"na1 app"}, now)
+				if err != nil {
+					t.Errorf("Take returned error: %v", err)
+					return
+				}
+				if wait == 0 {
+					mu.Lock()
+					taken++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if taken != 20 {
+		t.Errorf("expected 20 requests taken across both stores, got %d", taken)
+	}
+}
+
+func TestClientsShareRateLimitStore(t *testing.T) {
+	client, mux, _, teardown := createTestServer()
+	defer teardown()
+
+	used := 0
+	mux.HandleFunc("/lol/summoner/v3/summoners/1", func(w http.ResponseWriter, r *http.Request) {
+		used++
+		w.Header().Set(headerAppRateLimit, "2:10")
+		w.Header().Set(headerAppRateLimitCount, fmt.Sprintf("%d:10", used))
+		w.Header().Set(headerMethodRateLimit, "100:10")
+		w.Header().Set(headerMethodRateLimitCount, fmt.Sprintf("%d:10", used))
+		fmt.Fprint(w, `{"id": 1}`)
+	})
+
+	store := NewMemoryRateLimitStore()
+	WithRateLimitStore(store)(client)
+	other := NewClient("", WithRateLimitStore(store))
+	other.BaseURL = client.BaseURL
+
+	if _, _, err := client.Summoner.BySummonerID(1); err != nil {
+		t.Fatalf("Summoner.BySummonerID returned error: %v", err)
+	}
+	if _, _, err := other.Summoner.BySummonerID(1); err != nil {
+		t.Fatalf("Summoner.BySummonerID returned error: %v", err)
+	}
+	if _, resp, _ := client.Summoner.BySummonerID(1); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected 403 from the shared limit, got %d", resp.StatusCode)
+	}
+	if used != 2 {
+		t.Errorf("expected 2 requests to reach the server, got %d", used)
+	}
+}
+
+func TestRateLimitsKeptWithoutHeaders(t *testing.T) {
+	client, mux, _, teardown := createTestServer()
+	defer teardown()
+
+	requests := 0
+	mux.HandleFunc("/lol/summoner/v3/summoners/1", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set(headerAppRateLimit, "2:10")
+			w.Header().Set(headerAppRateLimitCount, "1:10")
+			fmt.Fprint(w, `{"id": 1}`)
+			return
+		}
+		// A headerless error, as returned by a proxy in front of the Riot API.
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	client.Summoner.BySummonerID(1)
+	client.Summoner.BySummonerID(1)
+	if _, resp, _ := client.Summoner.BySummonerID(1); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected 403 after a response without headers, got %d", resp.StatusCode)
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests, got %d", requests)
+	}
+	if limits := client.RateLimits(); len(limits) != 1 || len(limits[0].Windows) != 1 {
+		t.Errorf("expected the app limits to be kept, got %+v", limits)
+	}
+}
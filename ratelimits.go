@@ -0,0 +1,245 @@
+package ionia
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// appRateMethod is the method name under which application rate limits are tracked.
+const appRateMethod = "app"
+
+// RateLimitWindow is the state of a single rate limit window.
+type RateLimitWindow struct {
+	// Length of the window.
+	Seconds int
+
+	// Requests allowed in the window, and used so far.
+	Allowed int
+	Used    int
+
+	// Estimated time at which the window resets. The Riot API does not report
+	// when windows start, so this is measured from the first response seen in
+	// each window and may be later than the actual reset.
+	Reset time.Time
+}
+
+// Remaining returns the number of requests remaining in the window.
+func (w RateLimitWindow) Remaining() int {
+	if w.Used > w.Allowed {
+		return 0
+	}
+	return w.Allowed - w.Used
+}
+
+// RateLimitStatus is the state of the rate limit windows for an API key, platform and method.
+type RateLimitStatus struct {
+	// The API key the limits apply to, as returned by APIKeyID.
+	Key string
+
+	// Platform the limits apply to (e.g. na1).
+	Platform string
+
+	// The method name (e.g. GET_getMatch), or "" for the application rate limit.
+	Method string
+
+	// Windows ordered from shortest to longest.
+	Windows []RateLimitWindow
+}
+
+// RateLimitEvent is passed to rate limit subscribers when the headroom of a window
+// crosses their threshold.
+type RateLimitEvent struct {
+	Key      string
+	Platform string
+	Method   string
+	Window   RateLimitWindow
+
+	// Low is true when the headroom has fallen to or below the threshold,
+	// and false when it has recovered above it.
+	Low bool
+}
+
+type rateSubscription struct {
+	threshold float64
+	fn        func(RateLimitEvent)
+	// Whether each window was last seen at or below the threshold, keyed by limitKey and window length.
+	low map[string]map[int]bool
+}
+
+// RateLimits returns a snapshot of the rate limits reported by the Riot API in
+// the most recent response for each API key, platform and method.
+func (c *Client) RateLimits() []RateLimitStatus {
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+
+	statuses := make([]RateLimitStatus, 0, len(c.rateLimits))
+	for key, rate := range c.rateLimits {
+		id, platform, method := splitLimitKey(key)
+		if method == appRateMethod {
+			method = ""
+		}
+		statuses = append(statuses, RateLimitStatus{
+			Key:      id,
+			Platform: platform,
+			Method:   method,
+			Windows:  c.windows(key, rate),
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Platform != statuses[j].Platform {
+			return statuses[i].Platform < statuses[j].Platform
+		}
+		if statuses[i].Method != statuses[j].Method {
+			return statuses[i].Method < statuses[j].Method
+		}
+		return statuses[i].Key < statuses[j].Key
+	})
+	return statuses
+}
+
+// SubscribeRateLimits calls fn whenever the fraction of requests remaining in a
+// rate limit window falls to or below threshold, and again when it recovers.
+// fn is called from the goroutine which made the request, and should not block.
+// The returned function cancels the subscription.
+func (c *Client) SubscribeRateLimits(threshold float64, fn func(RateLimitEvent)) (cancel func()) {
+	sub := &rateSubscription{threshold: threshold, fn: fn, low: make(map[string]map[int]bool)}
+
+	c.rateMu.Lock()
+	c.rateSubs = append(c.rateSubs, sub)
+	c.rateMu.Unlock()
+
+	return func() {
+		c.rateMu.Lock()
+		defer c.rateMu.Unlock()
+		for i, s := range c.rateSubs {
+			if s == sub {
+				c.rateSubs = append(c.rateSubs[:i], c.rateSubs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// updateRates stores the rates parsed from a response and notifies subscribers.
+func (c *Client) updateRates(keyID, platform, method string, appRate, methodRate *Rate) {
+	now := time.Now()
+
+	var updates []struct {
+		key  string
+		rate *Rate
+	}
+	for _, u := range []struct {
+		key  string
+		rate *Rate
+	}{
+		{limitKey(keyID, platform, appRateMethod), appRate},
+		{limitKey(keyID, platform, method), methodRate},
+	} {
+		// Responses without rate limit headers, such as some errors, keep the last limits.
+		if u.rate != nil && len(u.rate.Limits) > 0 {
+			updates = append(updates, u)
+		}
+	}
+
+	c.rateMu.Lock()
+	var events []func()
+	for _, u := range updates {
+		c.updateResets(u.key, *u.rate, now)
+		c.rateLimits[u.key] = *u.rate
+		events = append(events, c.rateEvents(u.key, *u.rate)...)
+	}
+	c.rateMu.Unlock()
+
+	for _, u := range updates {
+		if err := c.rateStore.Update(u.key, *u.rate, now); err != nil && c.logger != nil {
+			c.logger.Warn("updating rate limit store failed", "error", c.redact(err.Error()))
+		}
+	}
+
+	for _, e := range events {
+		e()
+	}
+}
+
+// updateResets estimates when each window of rate resets. A window is assumed to
+// have started when it is first seen, when its count drops, or after its previous reset.
+func (c *Client) updateResets(key string, rate Rate, now time.Time) {
+	resets, ok := c.rateResets[key]
+	if !ok {
+		resets = make(map[int]time.Time)
+		c.rateResets[key] = resets
+	}
+	prev := c.rateLimits[key]
+	for s := range rate.Limits {
+		reset, ok := resets[s]
+		if !ok || !now.Before(reset) || rate.Counts[s].Used < prev.Counts[s].Used {
+			resets[s] = now.Add(time.Duration(s) * time.Second)
+		}
+	}
+}
+
+// rateEvents returns the notifications due to subscribers for the new state of rate.
+func (c *Client) rateEvents(key string, rate Rate) []func() {
+	var events []func()
+	id, platform, method := splitLimitKey(key)
+	if method == appRateMethod {
+		method = ""
+	}
+	for _, w := range c.windows(key, rate) {
+		if w.Allowed <= 0 {
+			continue
+		}
+		headroom := float64(w.Remaining()) / float64(w.Allowed)
+		for _, sub := range c.rateSubs {
+			low := headroom <= sub.threshold
+			states, ok := sub.low[key]
+			if !ok {
+				states = make(map[int]bool)
+				sub.low[key] = states
+			}
+			if states[w.Seconds] == low {
+				continue
+			}
+			states[w.Seconds] = low
+
+			fn, e := sub.fn, RateLimitEvent{Key: id, Platform: platform, Method: method, Window: w, Low: low}
+			events = append(events, func() { fn(e) })
+		}
+	}
+	return events
+}
+
+// windows returns the windows of rate, ordered from shortest to longest.
+func (c *Client) windows(key string, rate Rate) []RateLimitWindow {
+	windows := make([]RateLimitWindow, 0, len(rate.Limits))
+	for s, l := range rate.Limits {
+		windows = append(windows, RateLimitWindow{
+			Seconds: s,
+			Allowed: l.Allowed,
+			Used:    rate.Counts[s].Used,
+			Reset:   c.rateResets[key][s],
+		})
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i].Seconds < windows[j].Seconds })
+	return windows
+}
+
+// rateKey returns the key identifying a platform and method.
+func rateKey(platform, method string) string {
+	return platform + " " + method
+}
+
+// limitKey returns the key under which the rate limits of an API key, platform
+// and method are stored. keyID is the key's APIKeyID.
+func limitKey(keyID, platform, method string) string {
+	return keyID + " " + rateKey(platform, method)
+}
+
+func splitLimitKey(key string) (keyID, platform, method string) {
+	parts := strings.SplitN(key, " ", 3)
+	for len(parts) < 3 {
+		parts = append([]string{""}, parts...)
+	}
+	return parts[0], parts[1], parts[2]
+}
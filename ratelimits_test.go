@@ -0,0 +1,81 @@
+package ionia
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRateLimits(t *testing.T) {
+	client, mux, _, teardown := createTestServer()
+	defer teardown()
+
+	used := 0
+	mux.HandleFunc("/lol/summoner/v3/summoners/1", func(w http.ResponseWriter, r *http.Request) {
+		used++
+		w.Header().Set(headerAppRateLimit, "10:1,100:120")
+		w.Header().Set(headerAppRateLimitCount, fmt.Sprintf("%d:1,%d:120", used, used))
+		w.Header().Set(headerMethodRateLimit, "1000:10")
+		w.Header().Set(headerMethodRateLimitCount, fmt.Sprintf("%d:10", used))
+		fmt.Fprint(w, `{"id": 1}`)
+	})
+
+	var events []RateLimitEvent
+	cancel := client.SubscribeRateLimits(0.2, func(e RateLimitEvent) {
+		events = append(events, e)
+	})
+
+	start := time.Now()
+	for i := 0; i < 8; i++ {
+		if _, _, err := client.Summoner.BySummonerID(1); err != nil {
+			t.Fatalf("Summoner.BySummonerID returned error: %v", err)
+		}
+	}
+
+	limits := client.RateLimits()
+	if len(limits) != 2 {
+		t.Fatalf("expected 2 rate limit statuses, got %d: %+v", len(limits), limits)
+	}
+	app, method := limits[0], limits[1]
+	if app.Platform != "127.0.0.1" || app.Method != "" || method.Method != "GET_getBySummonerId" {
+		t.Errorf("unexpected statuses: %+v", limits)
+	}
+	var got []RateLimitWindow
+	for _, w := range app.Windows {
+		if w.Reset.Before(start.Add(time.Duration(w.Seconds)*time.Second)) || w.Reset.After(time.Now().Add(time.Duration(w.Seconds)*time.Second)) {
+			t.Errorf("unexpected reset time for %ds window: %v", w.Seconds, w.Reset)
+		}
+		w.Reset = time.Time{}
+		got = append(got, w)
+	}
+	want := []RateLimitWindow{{Seconds: 1, Allowed: 10, Used: 8}, {Seconds: 120, Allowed: 100, Used: 8}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected app windows: got %+v, want %+v", got, want)
+	}
+	if r := app.Windows[0].Remaining(); r != 2 {
+		t.Errorf("expected 2 remaining, got %d", r)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d: %+v", len(events), events)
+	}
+	if e := events[0]; !e.Low || e.Method != "" || e.Window.Seconds != 1 || e.Window.Used != 8 {
+		t.Errorf("unexpected event: %+v", e)
+	}
+
+	// The window resets, and headroom recovers.
+	used = 0
+	client.Summoner.BySummonerID(1)
+	if len(events) != 2 || events[1].Low || events[1].Window.Used != 1 {
+		t.Errorf("expected recovery event, got %+v", events)
+	}
+
+	cancel()
+	used = 8
+	client.Summoner.BySummonerID(1)
+	if len(events) != 2 {
+		t.Errorf("expected no events after cancelling, got %+v", events)
+	}
+}
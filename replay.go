@@ -0,0 +1,295 @@
+package ionia
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Spectator hosts which serve the replay protocol, keyed by platform ID.
+var spectatorHosts = map[string]string{
+	"BR1":  "spectator.br.lol.riotgames.com:80",
+	"EUN1": "spectator.eu.lol.riotgames.com:8088",
+	"EUW1": "spectator.euw1.lol.riotgames.com:80",
+	"JP1":  "spectator.jp1.lol.riotgames.com:80",
+	"KR":   "spectator.kr.lol.riotgames.com:80",
+	"LA1":  "spectator.la1.lol.riotgames.com:80",
+	"LA2":  "spectator.la2.lol.riotgames.com:80",
+	"NA1":  "spectator.na.lol.riotgames.com:80",
+	"OC1":  "spectator.oc1.lol.riotgames.com:80",
+	"PBE1": "spectator.pbe1.lol.riotgames.com:8088",
+	"RU":   "spectator.ru.lol.riotgames.com:80",
+	"TR1":  "spectator.tr.lol.riotgames.com:80",
+}
+
+const replayConsumerPath = "/observer-mode/rest/consumer/"
+
+// ReplayGame identifies a game to download with the spectator replay protocol.
+type ReplayGame struct {
+	PlatformID    string
+	GameID        int64
+	EncryptionKey string
+}
+
+// ReplayGameFromCurrentGame returns the replay information for a current game.
+func ReplayGameFromCurrentGame(g *CurrentGameInfo) ReplayGame {
+	return ReplayGame{PlatformID: g.PlatformID, GameID: g.GameID, EncryptionKey: g.Observers.EncryptionKey}
+}
+
+// ReplayGameFromFeaturedGame returns the replay information for a featured game.
+func ReplayGameFromFeaturedGame(g *FeaturedGameInfo) ReplayGame {
+	return ReplayGame{PlatformID: g.PlatformID, GameID: g.GameID, EncryptionKey: g.Observers.EncryptionKey}
+}
+
+// ReplayClient downloads game data using the spectator replay protocol.
+// Unlike the Riot API, the replay protocol is served by the spectator hosts
+// and does not require an API key.
+type ReplayClient struct {
+	client *http.Client
+
+	// The consumer endpoint of the spectator host,
+	// e.g. http://spectator.na.lol.riotgames.com:80/observer-mode/rest/consumer/.
+	BaseURL *url.URL
+}
+
+// ReplayClientOption is a function which modifies the replay client.
+type ReplayClientOption func(*ReplayClient)
+
+// WithSpectatorHost returns a ReplayClientOption which sets the spectator host
+// (e.g. "localhost:8080") the replay client connects to over plain HTTP.
+func WithSpectatorHost(host string) ReplayClientOption {
+	return func(r *ReplayClient) {
+		r.BaseURL, _ = url.Parse("http://" + host + replayConsumerPath)
+	}
+}
+
+// WithReplayHTTPClient returns a ReplayClientOption which sets the HTTP client used for requests.
+func WithReplayHTTPClient(c *http.Client) ReplayClientOption {
+	return func(r *ReplayClient) {
+		r.client = c
+	}
+}
+
+// NewReplayClient creates a replay client for the spectator host of the given platform.
+// An error is returned if the platform's host is not known and no host was given with
+// WithSpectatorHost.
+func NewReplayClient(platformID string, opts ...ReplayClientOption) (*ReplayClient, error) {
+	r := &ReplayClient{client: http.DefaultClient}
+	if host, ok := spectatorHosts[strings.ToUpper(platformID)]; ok {
+		WithSpectatorHost(host)(r)
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.BaseURL == nil {
+		return nil, fmt.Errorf("no spectator host known for platform %q", platformID)
+	}
+	return r, nil
+}
+
+// GameMetaData contains replay metadata for a game.
+type GameMetaData struct {
+	GameKey struct {
+		GameID     int64  `json:"gameId"`
+		PlatformID string `json:"platformId"`
+	} `json:"gameKey"`
+	GameServerAddress    string `json:"gameServerAddress"`
+	Port                 int    `json:"port"`
+	EncryptionKey        string `json:"encryptionKey"`
+	ChunkTimeInterval    int    `json:"chunkTimeInterval"`
+	StartTime            string `json:"startTime"`
+	GameEnded            bool   `json:"gameEnded"`
+	LastChunkID          int    `json:"lastChunkId"`
+	LastKeyFrameID       int    `json:"lastKeyFrameId"`
+	EndStartupChunkID    int    `json:"endStartupChunkId"`
+	DelayTime            int    `json:"delayTime"`
+	KeyFrameTimeInterval int    `json:"keyFrameTimeInterval"`
+	StartGameChunkID     int    `json:"startGameChunkId"`
+	GameLength           int    `json:"gameLength"`
+	EndGameChunkID       int    `json:"endGameChunkId"`
+	EndGameKeyFrameID    int    `json:"endGameKeyFrameId"`
+}
+
+// ChunkInfo describes the latest chunk and keyframe available for a game.
+type ChunkInfo struct {
+	ChunkID            int `json:"chunkId"`
+	AvailableSince     int `json:"availableSince"`
+	NextAvailableChunk int `json:"nextAvailableChunk"`
+	KeyFrameID         int `json:"keyFrameId"`
+	NextChunkID        int `json:"nextChunkId"`
+	EndStartupChunkID  int `json:"endStartupChunkId"`
+	StartGameChunkID   int `json:"startGameChunkId"`
+	EndGameChunkID     int `json:"endGameChunkId"`
+	Duration           int `json:"duration"`
+}
+
+// GameMetaData retrieves the replay metadata for a game, along with the raw response body.
+func (r *ReplayClient) GameMetaData(ctx context.Context, platformID string, gameID int64) (*GameMetaData, []byte, error) {
+	body, err := r.get(ctx, "getGameMetaData", platformID, gameID, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	md := &GameMetaData{}
+	if err := json.Unmarshal(body, md); err != nil {
+		return nil, body, err
+	}
+	return md, body, nil
+}
+
+// LastChunkInfo retrieves information about the latest chunk available for a game.
+func (r *ReplayClient) LastChunkInfo(ctx context.Context, platformID string, gameID int64) (*ChunkInfo, error) {
+	body, err := r.get(ctx, "getLastChunkInfo", platformID, gameID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	ci := &ChunkInfo{}
+	if err := json.Unmarshal(body, ci); err != nil {
+		return nil, err
+	}
+	return ci, nil
+}
+
+// GameDataChunk retrieves a game data chunk. The chunk is encrypted with the game's encryption key.
+func (r *ReplayClient) GameDataChunk(ctx context.Context, platformID string, gameID int64, chunkID int) ([]byte, error) {
+	return r.get(ctx, "getGameDataChunk", platformID, gameID, chunkID)
+}
+
+// KeyFrame retrieves a keyframe. The keyframe is encrypted with the game's encryption key.
+func (r *ReplayClient) KeyFrame(ctx context.Context, platformID string, gameID int64, keyFrameID int) ([]byte, error) {
+	return r.get(ctx, "getKeyFrame", platformID, gameID, keyFrameID)
+}
+
+func (r *ReplayClient) get(ctx context.Context, method, platformID string, gameID int64, id int) ([]byte, error) {
+	u, err := r.BaseURL.Parse(fmt.Sprintf("%s/%s/%d/%d/token", method, platformID, gameID, id))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &ReplayError{Method: method, StatusCode: resp.StatusCode}
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// ReplayError is returned when the spectator host responds with an error.
+type ReplayError struct {
+	Method     string
+	StatusCode int
+}
+
+func (e *ReplayError) Error() string {
+	return fmt.Sprintf("spectator %s returned error: %d", e.Method, e.StatusCode)
+}
+
+// Download saves the metadata, chunks and keyframes of a game into dir, polling for
+// new chunks until the game ends or ctx is done. Files are laid out as:
+//
+//	dir/metadata.json
+//	dir/encryption-key
+//	dir/chunks/<chunkId>
+//	dir/keyframes/<keyFrameId>
+//
+// Chunks which are no longer available on the spectator host are skipped.
+func (r *ReplayClient) Download(ctx context.Context, game ReplayGame, dir string) error {
+	for _, d := range []string{"chunks", "keyframes"} {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0755); err != nil {
+			return err
+		}
+	}
+
+	md, raw, err := r.GameMetaData(ctx, game.PlatformID, game.GameID)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "metadata.json"), raw, 0644); err != nil {
+		return err
+	}
+
+	key := game.EncryptionKey
+	if key == "" {
+		key = md.EncryptionKey
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "encryption-key"), []byte(key), 0644); err != nil {
+		return err
+	}
+
+	lastChunk, lastKeyFrame := 0, 0
+	for {
+		ci, err := r.LastChunkInfo(ctx, game.PlatformID, game.GameID)
+		if err != nil {
+			return err
+		}
+
+		for id := lastChunk + 1; id <= ci.ChunkID; id++ {
+			// Chunks between the end of startup and the start of the game are not served.
+			if id > ci.EndStartupChunkID && id < ci.StartGameChunkID {
+				continue
+			}
+			if err := r.save(ctx, dir, "chunks", id, r.GameDataChunk, game); err != nil {
+				return err
+			}
+		}
+		if ci.ChunkID > lastChunk {
+			lastChunk = ci.ChunkID
+		}
+
+		for id := lastKeyFrame + 1; id <= ci.KeyFrameID; id++ {
+			if err := r.save(ctx, dir, "keyframes", id, r.KeyFrame, game); err != nil {
+				return err
+			}
+		}
+		if ci.KeyFrameID > lastKeyFrame {
+			lastKeyFrame = ci.KeyFrameID
+		}
+
+		if ci.EndGameChunkID > 0 && lastChunk >= ci.EndGameChunkID {
+			return nil
+		}
+
+		wait := time.Duration(ci.NextAvailableChunk) * time.Millisecond
+		if wait <= 0 {
+			wait = time.Second
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+func (r *ReplayClient) save(ctx context.Context, dir, kind string, id int, fetch func(context.Context, string, int64, int) ([]byte, error), game ReplayGame) error {
+	data, err := fetch(ctx, game.PlatformID, game.GameID, id)
+	if err != nil {
+		if re, ok := err.(*ReplayError); ok && re.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, kind, strconv.Itoa(id)), data, 0644)
+}
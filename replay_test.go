@@ -0,0 +1,76 @@
+package ionia
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReplayDownload(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(replayConsumerPath+"getGameMetaData/NA1/42/0/token", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"gameKey": {"gameId": 42, "platformId": "NA1"}, "encryptionKey": "meta-key"}`)
+	})
+	mux.HandleFunc(replayConsumerPath+"getLastChunkInfo/NA1/42/0/token", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"chunkId": 3, "keyFrameId": 1, "endStartupChunkId": 1, "startGameChunkId": 3, "endGameChunkId": 3}`)
+	})
+	mux.HandleFunc(replayConsumerPath+"getGameDataChunk/NA1/42/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "chunk "+strings.Split(r.URL.Path, "/")[7])
+	})
+	mux.HandleFunc(replayConsumerPath+"getKeyFrame/NA1/42/1/token", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "keyframe 1")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	rc, err := NewReplayClient("NA1", WithSpectatorHost(strings.TrimPrefix(server.URL, "http://")))
+	if err != nil {
+		t.Fatalf("NewReplayClient returned error: %v", err)
+	}
+
+	dir, err := ioutil.TempDir("", "ionia-replay")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir returned error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	game := ReplayGameFromCurrentGame(&CurrentGameInfo{GameID: 42, PlatformID: "NA1", Observers: Observer{EncryptionKey: "key"}})
+	if err := rc.Download(context.Background(), game, dir); err != nil {
+		t.Fatalf("Download returned error: %v", err)
+	}
+
+	want := map[string]string{
+		"encryption-key": "key",
+		"chunks/1":       "chunk 1",
+		"chunks/3":       "chunk 3",
+		"keyframes/1":    "keyframe 1",
+	}
+	for name, content := range want {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Errorf("failed to read %s: %v", name, err)
+			continue
+		}
+		if string(b) != content {
+			t.Errorf("%s = %q, want %q", name, b, content)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "chunks", "2")); !os.IsNotExist(err) {
+		t.Errorf("expected chunk 2 not to be downloaded, got %v", err)
+	}
+}
+
+func TestNewReplayClient_UnknownPlatform(t *testing.T) {
+	if _, err := NewReplayClient("XX1"); err == nil {
+		t.Errorf("expected error for unknown platform")
+	}
+	if _, err := NewReplayClient("XX1", WithSpectatorHost("localhost:8080")); err != nil {
+		t.Errorf("NewReplayClient returned unexpected error: %v", err)
+	}
+}
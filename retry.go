@@ -0,0 +1,136 @@
+package ionia
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryOption is a function which modifies the RetryOptions.
+type RetryOption func(*RetryOptions)
+
+// RetryOptions specifies the optional parameters for WithRetry.
+type RetryOptions struct {
+	// The maximum number of times a request is sent, including the first. Default: 3
+	MaxAttempts int
+
+	// The delay before the first retry, which doubles for each further retry
+	// up to MaxBackoff. Each delay is randomly reduced by up to half.
+	// Default: 500 milliseconds
+	MinBackoff time.Duration
+
+	// Default: 10 seconds
+	MaxBackoff time.Duration
+
+	// Response status codes which are retried. Default: 500, 502, 503, 504
+	StatusCodes []int
+
+	// HTTP methods which are retried. Default: GET
+	Methods []string
+
+	// RetryError reports whether a request which failed with err is retried.
+	// Default: every error is retried, unless the request's context is done.
+	RetryError func(err error) bool
+}
+
+// WithRetry returns a ClientOption which retries requests that fail with a
+// transport error or a retryable status code, waiting with exponential backoff
+// between attempts. Each attempt is checked against the rate limits again, and a
+// Retry-After header longer than the backoff is respected.
+func WithRetry(opts ...RetryOption) ClientOption {
+	options := &RetryOptions{
+		MaxAttempts: 3,
+		MinBackoff:  500 * time.Millisecond,
+		MaxBackoff:  10 * time.Second,
+		StatusCodes: []int{
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		Methods:    []string{http.MethodGet},
+		RetryError: func(err error) bool { return true },
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return func(c *Client) {
+		c.retry = options
+	}
+}
+
+// retryDelay reports whether req should be sent again after the given attempt
+// returned resp or err, and how long to wait first.
+func (o *RetryOptions) retryDelay(req *http.Request, attempt int, resp *http.Response, err error) (time.Duration, bool) {
+	if o == nil || attempt >= o.MaxAttempts || req.Context().Err() != nil {
+		return 0, false
+	}
+	if !containsString(o.Methods, req.Method) {
+		return 0, false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return 0, false
+	}
+	if err != nil {
+		if o.RetryError != nil && !o.RetryError(err) {
+			return 0, false
+		}
+	} else if !containsStatus(o.StatusCodes, resp.StatusCode) {
+		return 0, false
+	}
+
+	backoff := o.MaxBackoff
+	if d := o.MinBackoff << uint(attempt-1); d > 0 && d < backoff {
+		backoff = d
+	}
+	if backoff > 0 {
+		backoff -= time.Duration(rand.Int63n(int64(backoff/2) + 1))
+	}
+	if resp != nil {
+		if s, err := strconv.Atoi(resp.Header.Get(headerRetryAfter)); err == nil {
+			if d := time.Duration(s) * time.Second; d > backoff {
+				backoff = d
+			}
+		}
+	}
+	return backoff, true
+}
+
+// sleepRetry waits d before a retry, and rewinds the request body.
+func sleepRetry(req *http.Request, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return err
+		}
+		req.Body = body
+	}
+	return nil
+}
+
+func containsString(s []string, v string) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsStatus(s []int, v int) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package ionia
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func fastRetry(o *RetryOptions) {
+	o.MinBackoff = time.Millisecond
+	o.MaxBackoff = 2 * time.Millisecond
+}
+
+func TestRetry(t *testing.T) {
+	tt := []struct {
+		name         string
+		method       string
+		statuses     []int
+		opts         []RetryOption
+		wantRequests int
+		wantStatus   int
+	}{
+		{"Success", http.MethodGet, []int{http.StatusOK}, nil, 1, http.StatusOK},
+		{"Recovers", http.MethodGet, []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK}, nil, 3, http.StatusOK},
+		{"Attempts Exhausted", http.MethodGet, []int{500, 500, 500, 500}, nil, 3, http.StatusInternalServerError},
+		{"Not Retryable", http.MethodGet, []int{http.StatusNotFound, http.StatusOK}, nil, 1, http.StatusNotFound},
+		{"Not Idempotent", http.MethodPost, []int{http.StatusServiceUnavailable, http.StatusOK}, nil, 1, http.StatusServiceUnavailable},
+		{"Methods", http.MethodPost, []int{http.StatusServiceUnavailable, http.StatusOK}, []RetryOption{func(o *RetryOptions) { o.Methods = []string{http.MethodPost} }}, 2, http.StatusOK},
+		{"Status Codes", http.MethodGet, []int{http.StatusTooManyRequests, http.StatusOK}, []RetryOption{func(o *RetryOptions) { o.StatusCodes = []int{http.StatusTooManyRequests} }}, 2, http.StatusOK},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			client, mux, _, teardown := createTestServer()
+			defer teardown()
+			WithRetry(append([]RetryOption{fastRetry}, tc.opts...)...)(client)
+
+			requests := 0
+			mux.HandleFunc("/retry", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.statuses[requests])
+				requests++
+			})
+
+			var body interface{}
+			if tc.method == http.MethodPost {
+				body = map[string]int{"id": 1}
+			}
+			req, _ := client.NewRequest(tc.method, "retry", body)
+			resp, _ := client.Do(req, nil)
+			if requests != tc.wantRequests {
+				t.Errorf("expected %d requests, got %d", tc.wantRequests, requests)
+			}
+			if resp.StatusCode != tc.wantStatus {
+				t.Errorf("expected status %d, got %d", tc.wantStatus, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestRetryTransportError(t *testing.T) {
+	attempts := 0
+	rt := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		attempts++
+		if attempts < 3 {
+			return nil, errors.New("connection reset")
+		}
+		rec := httptest.NewRecorder()
+		fmt.Fprint(rec, `{"id": 1}`)
+		return rec.Result(), nil
+	})
+	logger := &testLogger{}
+	m := NewMetrics()
+	c := NewClient("", WithTransport(rt), WithRetry(fastRetry), WithLogger(logger), WithMetrics(m))
+
+	s, _, err := c.Summoner.BySummonerID(1)
+	if err != nil {
+		t.Fatalf("Summoner.BySummonerID returned error: %v", err)
+	}
+	if s.ID != 1 || attempts != 3 {
+		t.Errorf("expected summoner 1 after 3 attempts, got %+v after %d", s, attempts)
+	}
+
+	var retries []logEntry
+	for _, e := range logger.entries {
+		if e.msg == "retrying riot api request" {
+			retries = append(retries, e)
+		}
+	}
+	if len(retries) != 2 || retries[1].args["attempt"] != 2 || !strings.HasSuffix(retries[1].args["error"].(string), "connection reset") {
+		t.Errorf("unexpected retry log entries: %+v", retries)
+	}
+
+	var b bytes.Buffer
+	m.WriteTo(&b)
+	if want := `ionia_retries_total{platform="na1",method="GET_getBySummonerId"} 2`; !strings.Contains(b.String(), want) {
+		t.Errorf("metrics missing %q:\n%s", want, b.String())
+	}
+
+	attempts = 0
+	c = NewClient("", WithTransport(rt), WithRetry(fastRetry, func(o *RetryOptions) {
+		o.RetryError = func(err error) bool { return false }
+	}))
+	if _, _, err := c.Summoner.BySummonerID(1); err == nil || attempts != 1 {
+		t.Errorf("expected error without retrying, got %v after %d attempts", err, attempts)
+	}
+}
+
+func TestRetryChecksRateLimit(t *testing.T) {
+	client, mux, _, teardown := createTestServer()
+	defer teardown()
+	WithRetry(fastRetry)(client)
+
+	requests := 0
+	mux.HandleFunc("/lol/summoner/v3/summoners/1", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set(headerAppRateLimit, "1:10")
+		w.Header().Set(headerAppRateLimitCount, "1:10")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	// The app limit is used up by the first attempt, so the retry is denied.
+	if _, resp, _ := client.Summoner.BySummonerID(1); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", resp.StatusCode)
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	o := &RetryOptions{
+		MaxAttempts: 10,
+		MinBackoff:  time.Second,
+		MaxBackoff:  5 * time.Second,
+		StatusCodes: []int{http.StatusServiceUnavailable},
+		Methods:     []string{http.MethodGet},
+	}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	resp := &http.Response{StatusCode: http.StatusServiceUnavailable, Header: make(http.Header)}
+
+	for attempt, max := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second} {
+		d, ok := o.retryDelay(req, attempt+1, resp, nil)
+		if !ok || d < max/2 || d > max {
+			t.Errorf("attempt %d: expected delay between %v and %v, got %v, %v", attempt+1, max/2, max, d, ok)
+		}
+	}
+
+	resp.Header.Set(headerRetryAfter, "30")
+	if d, _ := o.retryDelay(req, 1, resp, nil); d != 30*time.Second {
+		t.Errorf("expected Retry-After delay of 30s, got %v", d)
+	}
+}
@@ -0,0 +1,326 @@
+package ionia
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Priority is the priority class of a Client's requests in a Scheduler.
+type Priority int
+
+// Priority classes.
+const (
+	// PriorityLow is for bulk work, such as backfilling match history.
+	PriorityLow Priority = -1
+
+	// PriorityNormal is the default.
+	PriorityNormal Priority = 0
+
+	// PriorityHigh is for interactive lookups which a user is waiting on.
+	PriorityHigh Priority = 1
+)
+
+// SchedulerOption is a function which modifies the SchedulerOptions.
+type SchedulerOption func(*SchedulerOptions)
+
+// SchedulerOptions specifies the optional parameters for NewScheduler.
+type SchedulerOptions struct {
+	// A queued request is promoted one priority class for each Aging it has
+	// waited, so that low priority requests are not starved. Default: 10 seconds.
+	Aging time.Duration
+}
+
+// Scheduler queues the requests of the Clients it is attached to with WithScheduler
+// while their rate limits are exhausted, instead of denying them. Queued requests
+// are sent in priority order as the limits allow. Within a priority class, tenants
+// share the limits in proportion to their weights.
+//
+//	s := ionia.NewScheduler()
+//	web := ionia.NewClient(key, ionia.WithScheduler(s), ionia.WithPriority(ionia.PriorityHigh))
+//	backfill := ionia.NewClient(key, ionia.WithScheduler(s), ionia.WithPriority(ionia.PriorityLow))
+//
+// Clients sharing a Scheduler should also share a RateLimitStore. The priority and
+// tenant of a single request can be set with WithRequestPriority and WithRequestTenant.
+type Scheduler struct {
+	aging time.Duration
+
+	mu      sync.Mutex
+	queue   []*scheduled
+	tenants map[string]float64
+	vclock  float64
+	seq     uint64
+	timer   *time.Timer
+
+	dispatching bool
+	redispatch  bool
+}
+
+// scheduled is a request waiting in a Scheduler.
+type scheduled struct {
+	ctx      context.Context
+	store    RateLimitStore
+	keys     []string
+	priority Priority
+	queued   time.Time
+	// finish is the virtual finish time of the request within its tenant, used
+	// for weighted fair queuing.
+	finish float64
+	seq    uint64
+	// ready receives the result of taking the request from the store.
+	ready chan error
+}
+
+// NewScheduler creates a Scheduler with an empty queue.
+func NewScheduler(opts ...SchedulerOption) *Scheduler {
+	options := &SchedulerOptions{
+		Aging: 10 * time.Second,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	return &Scheduler{
+		aging:   options.Aging,
+		tenants: make(map[string]float64),
+	}
+}
+
+// WithScheduler returns a ClientOption which queues the Client's requests in s
+// while its rate limits are exhausted.
+func WithScheduler(s *Scheduler) ClientOption {
+	return func(c *Client) {
+		c.scheduler = s
+	}
+}
+
+// WithPriority returns a ClientOption which sets the priority class of the Client's
+// requests in its Scheduler. Default: PriorityNormal. It can be overridden for a
+// single request with WithRequestPriority.
+func WithPriority(p Priority) ClientOption {
+	return func(c *Client) {
+		c.priority = p
+	}
+}
+
+// WithTenant returns a ClientOption which sets the tenant the Client's requests are
+// queued under in its Scheduler, and the tenant's weight. Within a priority class,
+// a tenant with weight 2 is sent twice as many requests as one with weight 1.
+// Default: the tenant "" with weight 1. It can be overridden for a single request
+// with WithRequestTenant.
+func WithTenant(name string, weight int) ClientOption {
+	return func(c *Client) {
+		if weight < 1 {
+			weight = 1
+		}
+		c.tenant, c.tenantWeight = name, weight
+	}
+}
+
+// WithRequestPriority returns a copy of ctx which sets the priority class in the
+// Client's Scheduler of requests made with it, overriding WithPriority. The service
+// methods do not take a context, so it only applies to requests built with
+// NewRequest and sent with Do:
+//
+//	req, _ := client.NewRequest(http.MethodGet, "lol/summoner/v3/summoners/by-name/faker", nil)
+//	ctx := ionia.WithRequestPriority(req.Context(), ionia.PriorityHigh)
+//	client.Do(req.WithContext(ctx), &summoner)
+func WithRequestPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, requestPriorityKey{}, p)
+}
+
+// WithRequestTenant returns a copy of ctx which sets the tenant and weight in the
+// Client's Scheduler of requests made with it, overriding WithTenant. Like
+// WithRequestPriority, it only applies to requests sent with Do.
+func WithRequestTenant(ctx context.Context, name string, weight int) context.Context {
+	if weight < 1 {
+		weight = 1
+	}
+	return context.WithValue(ctx, requestTenantKey{}, requestTenant{name, weight})
+}
+
+type requestPriorityKey struct{}
+
+type requestTenantKey struct{}
+
+type requestTenant struct {
+	name   string
+	weight int
+}
+
+// schedule returns the priority, tenant and weight of req in the Client's
+// Scheduler, from its context or the Client's defaults.
+func (c *Client) schedule(req *http.Request) (Priority, string, int) {
+	p, tenant, weight := c.priority, c.tenant, c.tenantWeight
+	if rp, ok := req.Context().Value(requestPriorityKey{}).(Priority); ok {
+		p = rp
+	}
+	if rt, ok := req.Context().Value(requestTenantKey{}).(requestTenant); ok {
+		tenant, weight = rt.name, rt.weight
+	}
+	return p, tenant, weight
+}
+
+// wait blocks until the request can be taken from the store for all keys, or
+// the request's context is done.
+func (s *Scheduler) wait(req *http.Request, store RateLimitStore, keys []string, p Priority, tenant string, weight int) error {
+	s.mu.Lock()
+	start := s.tenants[tenant]
+	if start < s.vclock {
+		start = s.vclock
+	}
+	s.seq++
+	e := &scheduled{
+		ctx:      req.Context(),
+		store:    store,
+		keys:     keys,
+		priority: p,
+		queued:   time.Now(),
+		finish:   start + 1/float64(weight),
+		seq:      s.seq,
+		ready:    make(chan error, 1),
+	}
+	s.tenants[tenant] = e.finish
+	s.queue = append(s.queue, e)
+	s.mu.Unlock()
+	s.dispatch()
+
+	select {
+	case err := <-e.ready:
+		return err
+	case <-req.Context().Done():
+		s.mu.Lock()
+		removed := s.remove(e)
+		s.mu.Unlock()
+		if removed {
+			return req.Context().Err()
+		}
+		// The request is being taken from the store by the dispatcher.
+		return <-e.ready
+	}
+}
+
+// dispatch sends queued requests in order while the limits allow, and schedules
+// the next dispatch for when a window resets. Only the first queued request for
+// a key is taken from the store; requests which share a key with a request that
+// is waiting stay queued behind it. The store is called without s.mu held, and
+// only by one dispatch at a time.
+func (s *Scheduler) dispatch() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.dispatching {
+		s.redispatch = true
+		return
+	}
+	s.dispatching = true
+	defer func() { s.dispatching = false }()
+
+	for {
+		s.redispatch = false
+		now := time.Now()
+		sort.SliceStable(s.queue, func(i, j int) bool {
+			return s.before(s.queue[i], s.queue[j], now)
+		})
+
+		var next time.Duration
+		blocked := make(map[string]bool)
+		for {
+			e := s.head(blocked)
+			if e == nil {
+				break
+			}
+			s.remove(e)
+			if err := e.ctx.Err(); err != nil {
+				e.ready <- err
+				continue
+			}
+
+			s.mu.Unlock()
+			wait, err := e.store.Take(e.keys, now)
+			s.mu.Lock()
+
+			if err != nil {
+				err = fmt.Errorf("rate limit store: %v", err)
+			}
+			if err == nil && wait > 0 {
+				if err := e.ctx.Err(); err != nil {
+					e.ready <- err
+					continue
+				}
+				for _, key := range e.keys {
+					blocked[key] = true
+				}
+				if next == 0 || wait < next {
+					next = wait
+				}
+				s.queue = append(s.queue, e)
+				continue
+			}
+			if e.finish > s.vclock {
+				s.vclock = e.finish
+			}
+			e.ready <- err
+		}
+		if s.redispatch {
+			continue
+		}
+
+		if s.timer != nil {
+			s.timer.Stop()
+			s.timer = nil
+		}
+		if len(s.queue) > 0 {
+			s.timer = time.AfterFunc(next, s.dispatch)
+		}
+		return
+	}
+}
+
+// head returns the first queued request which shares no key with the blocked keys.
+func (s *Scheduler) head(blocked map[string]bool) *scheduled {
+	for _, e := range s.queue {
+		free := true
+		for _, key := range e.keys {
+			if blocked[key] {
+				free = false
+				break
+			}
+		}
+		if free {
+			return e
+		}
+	}
+	return nil
+}
+
+// before reports whether a should be sent before b.
+func (s *Scheduler) before(a, b *scheduled, now time.Time) bool {
+	if pa, pb := s.effective(a, now), s.effective(b, now); pa != pb {
+		return pa > pb
+	}
+	if a.finish != b.finish {
+		return a.finish < b.finish
+	}
+	return a.seq < b.seq
+}
+
+// effective returns the priority of a queued request, after aging.
+func (s *Scheduler) effective(e *scheduled, now time.Time) Priority {
+	if s.aging <= 0 {
+		return e.priority
+	}
+	return e.priority + Priority(now.Sub(e.queued)/s.aging)
+}
+
+// remove removes e from the queue, reporting whether it was still queued.
+func (s *Scheduler) remove(e *scheduled) bool {
+	for i, q := range s.queue {
+		if q == e {
+			s.queue = append(s.queue[:i], s.queue[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
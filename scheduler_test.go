@@ -0,0 +1,243 @@
+package ionia
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testSchedulerStore allows requests only when the test releases them.
+type testSchedulerStore struct {
+	mu    sync.Mutex
+	allow int
+	takes int
+	// The wait returned while no requests are allowed. Default: 1ms
+	wait time.Duration
+	// Called by Take, if set.
+	onTake func()
+}
+
+func (s *testSchedulerStore) Take(keys []string, now time.Time) (time.Duration, error) {
+	if s.onTake != nil {
+		s.onTake()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.takes++
+	if s.allow > 0 {
+		s.allow--
+		return 0, nil
+	}
+	if s.wait > 0 {
+		return s.wait, nil
+	}
+	return time.Millisecond, nil
+}
+
+func (s *testSchedulerStore) Update(key string, rate Rate, now time.Time) error { return nil }
+
+func (s *testSchedulerStore) release() {
+	s.mu.Lock()
+	s.allow++
+	s.mu.Unlock()
+}
+
+func queueLen(s *Scheduler) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.queue)
+}
+
+func TestScheduler(t *testing.T) {
+	type request struct {
+		name     string
+		priority Priority
+		tenant   string
+		weight   int
+		delay    time.Duration
+	}
+	tt := []struct {
+		name     string
+		aging    time.Duration
+		requests []request
+		want     []string
+	}{
+		{
+			name: "Priority",
+			requests: []request{
+				{name: "low 1", priority: PriorityLow},
+				{name: "low 2", priority: PriorityLow},
+				{name: "normal", priority: PriorityNormal},
+				{name: "high", priority: PriorityHigh},
+			},
+			want: []string{"high", "normal", "low 1", "low 2"},
+		},
+		{
+			name: "Tenant Weights",
+			requests: []request{
+				{name: "a 1", tenant: "a", weight: 2},
+				{name: "a 2", tenant: "a", weight: 2},
+				{name: "a 3", tenant: "a", weight: 2},
+				{name: "b 1", tenant: "b", weight: 1},
+				{name: "b 2", tenant: "b", weight: 1},
+				{name: "b 3", tenant: "b", weight: 1},
+			},
+			want: []string{"a 1", "a 2", "b 1", "a 3", "b 2", "b 3"},
+		},
+		{
+			name:  "Aging",
+			aging: 5 * time.Millisecond,
+			requests: []request{
+				{name: "low", priority: PriorityLow, delay: 20 * time.Millisecond},
+				{name: "high", priority: PriorityHigh},
+			},
+			want: []string{"low", "high"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewScheduler(func(o *SchedulerOptions) { o.Aging = tc.aging })
+			store := &testSchedulerStore{}
+
+			var mu sync.Mutex
+			var got []string
+			done := make(chan struct{}, len(tc.requests))
+			for i, r := range tc.requests {
+				r := r
+				weight := r.weight
+				if weight == 0 {
+					weight = 1
+				}
+				go func() {
+					req := httptest.NewRequest(http.MethodGet, "/", nil)
+					if err := s.wait(req, store, []string{"key"}, r.priority, r.tenant, weight); err != nil {
+						t.Errorf("%s: wait returned error: %v", r.name, err)
+					}
+					mu.Lock()
+					got = append(got, r.name)
+					mu.Unlock()
+					done <- struct{}{}
+				}()
+				for queueLen(s) != i+1 {
+					time.Sleep(time.Millisecond)
+				}
+				time.Sleep(r.delay)
+			}
+
+			for range tc.requests {
+				store.release()
+				<-done
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("unexpected order: got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSchedulerCancel(t *testing.T) {
+	s := NewScheduler()
+	store := &testSchedulerStore{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- s.wait(req, store, []string{"key"}, PriorityNormal, "", 1)
+	}()
+	for queueLen(s) != 1 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-errs; err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if n := queueLen(s); n != 0 {
+		t.Errorf("expected empty queue, got %d", n)
+	}
+}
+
+func TestSchedulerDispatch(t *testing.T) {
+	s := NewScheduler()
+	store := &testSchedulerStore{wait: time.Hour}
+	// The store may call back into the Scheduler, as it is called without s.mu held.
+	store.onTake = func() { queueLen(s) }
+
+	done := make(chan struct{}, 4)
+	for i := 0; i < 3; i++ {
+		go func() {
+			s.wait(httptest.NewRequest(http.MethodGet, "/", nil), store, []string{"na1 app", "na1 GET_getMatch"}, PriorityNormal, "", 1)
+			done <- struct{}{}
+		}()
+		for queueLen(s) != i+1 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	go func() {
+		s.wait(httptest.NewRequest(http.MethodGet, "/", nil), store, []string{"euw1 app"}, PriorityNormal, "", 1)
+		done <- struct{}{}
+	}()
+	for queueLen(s) != 4 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Only the first request for a key is taken, but other keys are not held up.
+	store.mu.Lock()
+	store.takes = 0
+	store.mu.Unlock()
+	s.dispatch()
+	store.mu.Lock()
+	takes := store.takes
+	store.allow = 4
+	store.mu.Unlock()
+	if takes != 2 {
+		t.Errorf("expected 2 takes, got %d", takes)
+	}
+
+	s.dispatch()
+	for i := 0; i < 4; i++ {
+		<-done
+	}
+}
+
+func TestRequestPriority(t *testing.T) {
+	client := NewClient("", WithPriority(PriorityLow), WithTenant("backfill", 1))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if p, tenant, weight := client.schedule(req); p != PriorityLow || tenant != "backfill" || weight != 1 {
+		t.Errorf("schedule = %v, %q, %d; want the client's defaults", p, tenant, weight)
+	}
+
+	ctx := WithRequestTenant(WithRequestPriority(req.Context(), PriorityHigh), "web", 3)
+	if p, tenant, weight := client.schedule(req.WithContext(ctx)); p != PriorityHigh || tenant != "web" || weight != 3 {
+		t.Errorf("schedule = %v, %q, %d; want PriorityHigh, web, 3", p, tenant, weight)
+	}
+}
+
+func TestClientScheduler(t *testing.T) {
+	client, mux, _, teardown := createTestServer()
+	defer teardown()
+
+	mux.HandleFunc("/lol/summoner/v3/summoners/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(headerAppRateLimit, "1:1")
+		w.Header().Set(headerAppRateLimitCount, "1:1")
+		w.Header().Set(headerMethodRateLimit, "100:1")
+		w.Header().Set(headerMethodRateLimitCount, "1:1")
+		fmt.Fprint(w, `{"id": 1}`)
+	})
+	WithScheduler(NewScheduler())(client)
+	WithPriority(PriorityHigh)(client)
+	WithTenant("web", 2)(client)
+
+	// The second request is queued until the app window resets, instead of being denied.
+	for i := 0; i < 2; i++ {
+		if _, resp, err := client.Summoner.BySummonerID(1); err != nil || resp.StatusCode != http.StatusOK {
+			t.Fatalf("Summoner.BySummonerID returned %v, %v", resp, err)
+		}
+	}
+}
@@ -0,0 +1,421 @@
+package ionia
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// EnrichedGame is a current game along with scouting information about each participant.
+type EnrichedGame struct {
+	Game         *CurrentGameInfo
+	Participants []EnrichedParticipant
+
+	// Errors encountered while fetching static data.
+	// Names are left empty when static data could not be fetched.
+	Errors []error
+}
+
+// EnrichedParticipant is a current game participant along with their ranked
+// positions, mastery on the picked champion, recent results and resolved static names.
+type EnrichedParticipant struct {
+	CurrentGameParticipant
+
+	ChampionName     string
+	Spell1Name       string
+	Spell2Name       string
+	PerkStyleName    string
+	PerkSubStyleName string
+
+	Positions []LeaguePositionDTO
+	Mastery   *ChampionMasteryDTO
+
+	// The number of recent matches looked at, and how many of them were won.
+	RecentGames int
+	RecentWins  int
+
+	// Errors encountered while enriching this participant.
+	// The fields above are left empty for any lookup that failed.
+	Errors []error
+}
+
+// RecentWinRate returns the fraction of the participant's recent matches that were won.
+func (p *EnrichedParticipant) RecentWinRate() float64 {
+	if p.RecentGames == 0 {
+		return 0
+	}
+	return float64(p.RecentWins) / float64(p.RecentGames)
+}
+
+// EnrichError describes a failed lookup while enriching a current game.
+type EnrichError struct {
+	// The summoner being looked up. This is 0 for static data lookups.
+	SummonerID int64
+
+	// The lookup which failed (e.g. "positions", "mastery", "static champions").
+	Lookup string
+
+	Err error
+}
+
+func (e *EnrichError) Error() string {
+	if e.SummonerID == 0 {
+		return fmt.Sprintf("enrich %s: %v", e.Lookup, e.Err)
+	}
+	return fmt.Sprintf("enrich %s for summoner %d: %v", e.Lookup, e.SummonerID, e.Err)
+}
+
+// EnrichOptions specifies the optional parameters for enriching a current game.
+type EnrichOptions struct {
+	// The maximum number of requests in flight at once.
+	// Default: 4.
+	Concurrency int
+
+	// The number of recent matches per participant used to compute the recent win rate.
+	// Each match costs one request per participant. Zero disables the lookup.
+	// Default: 5.
+	RecentMatches int
+
+	// If true, static data is not fetched and names are left empty.
+	SkipStaticData bool
+
+	// How long static data is reused before it is fetched again. Static data is
+	// fetched once per Client, since its rate limits allow only a few requests
+	// an hour. Default: 24 hours.
+	StaticDataMaxAge time.Duration
+}
+
+// EnrichOption is a function which modifies the EnrichOptions.
+type EnrichOption func(*EnrichOptions)
+
+// EnrichedCurrentGame retrieves the current game for the given summoner ID and enriches it.
+// The returned response is that of the current game request; failed enrichment
+// lookups do not cause an error and are instead recorded on the returned game.
+func (s *SpectatorService) EnrichedCurrentGame(summonerID int64, opts ...EnrichOption) (*EnrichedGame, *http.Response, error) {
+	game, resp, err := s.CurrentGame(summonerID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return s.EnrichCurrentGame(game, opts...), resp, nil
+}
+
+// EnrichCurrentGame fetches each participant's ranked positions, mastery on their
+// picked champion and recent win rate, and resolves champion, summoner spell and
+// rune path names. Lookups run concurrently and failures are recorded on the
+// returned game rather than aborting the enrichment. Bots are not looked up.
+//
+// Without WithScheduler, lookups denied by the rate limits fail immediately and
+// are recorded as an EnrichError, rather than waiting for the limits to reset.
+// Static data which could not be fetched is fetched again on the next call.
+func (s *SpectatorService) EnrichCurrentGame(game *CurrentGameInfo, opts ...EnrichOption) *EnrichedGame {
+	options := &EnrichOptions{
+		Concurrency:      4,
+		RecentMatches:    5,
+		StaticDataMaxAge: 24 * time.Hour,
+	}
+	for _, o := range opts {
+		o(options)
+	}
+	if options.Concurrency < 1 {
+		options.Concurrency = 1
+	}
+
+	e := &enricher{
+		client: s.client,
+		sem:    make(chan struct{}, options.Concurrency),
+	}
+
+	eg := &EnrichedGame{
+		Game:         game,
+		Participants: make([]EnrichedParticipant, len(game.Participants)),
+	}
+
+	var wg sync.WaitGroup
+	var static staticNames
+	if !options.SkipStaticData {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			static, eg.Errors = e.staticNames(options.StaticDataMaxAge)
+		}()
+	}
+
+	for i, p := range game.Participants {
+		ep := &eg.Participants[i]
+		ep.CurrentGameParticipant = p
+		if p.Bot || p.SummonerID == 0 {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e.participant(ep, options.RecentMatches)
+		}()
+	}
+	wg.Wait()
+
+	for i := range eg.Participants {
+		static.resolve(&eg.Participants[i])
+	}
+
+	return eg
+}
+
+type enricher struct {
+	client *Client
+	sem    chan struct{}
+}
+
+// call runs f once a request slot is free and converts non-OK responses into errors.
+func (e *enricher) call(f func() (*http.Response, error)) error {
+	e.sem <- struct{}{}
+	defer func() { <-e.sem }()
+
+	return checkResponse(f())
+}
+
+func (e *enricher) participant(ep *EnrichedParticipant, recentMatches int) {
+	var mu sync.Mutex
+	fail := func(lookup string, err error) {
+		mu.Lock()
+		ep.Errors = append(ep.Errors, &EnrichError{SummonerID: ep.SummonerID, Lookup: lookup, Err: err})
+		mu.Unlock()
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		var positions []LeaguePositionDTO
+		err := e.call(func() (resp *http.Response, err error) {
+			positions, resp, err = e.client.League.PositionsBySummonerID(ep.SummonerID)
+			return resp, err
+		})
+		if err != nil {
+			fail("positions", err)
+			return
+		}
+		ep.Positions = positions
+	}()
+	go func() {
+		defer wg.Done()
+		var mastery *ChampionMasteryDTO
+		err := e.call(func() (resp *http.Response, err error) {
+			mastery, resp, err = e.client.ChampionMastery.BySummonerAndChampionID(ep.SummonerID, ep.ChampionID)
+			return resp, err
+		})
+		if err != nil {
+			fail("mastery", err)
+			return
+		}
+		ep.Mastery = mastery
+	}()
+
+	if recentMatches > 0 {
+		games, wins, err := e.recentResults(ep.SummonerID, recentMatches)
+		if err != nil {
+			fail("recent matches", err)
+		}
+		ep.RecentGames, ep.RecentWins = games, wins
+	}
+
+	wg.Wait()
+}
+
+// recentResults looks up the outcome of the summoner's most recent matches.
+// Matches which fail to load are skipped and reported in the returned error.
+func (e *enricher) recentResults(summonerID int64, n int) (games, wins int, err error) {
+	var summoner *SummonerDTO
+	err = e.call(func() (resp *http.Response, err error) {
+		summoner, resp, err = e.client.Summoner.BySummonerID(summonerID)
+		return resp, err
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var recent *MatchlistDTO
+	err = e.call(func() (resp *http.Response, err error) {
+		recent, resp, err = e.client.Match.RecentMatches(summoner.AccountID)
+		return resp, err
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	refs := recent.Matches
+	if len(refs) > n {
+		refs = refs[:n]
+	}
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		failed int
+	)
+	for _, ref := range refs {
+		wg.Add(1)
+		go func(gameID int64) {
+			defer wg.Done()
+			var match *MatchDTO
+			err := e.call(func() (resp *http.Response, err error) {
+				match, resp, err = e.client.Match.MatchByID(gameID)
+				return resp, err
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed++
+				return
+			}
+			if win, ok := matchWon(match, summoner); ok {
+				games++
+				if win {
+					wins++
+				}
+			}
+		}(ref.GameID)
+	}
+	wg.Wait()
+
+	if failed > 0 {
+		err = fmt.Errorf("%d of %d matches could not be retrieved", failed, len(refs))
+	}
+	return games, wins, err
+}
+
+// matchWon reports whether the given summoner won the match.
+// The second return value is false if the summoner is not in the match.
+func matchWon(m *MatchDTO, s *SummonerDTO) (win, ok bool) {
+	participantID := 0
+	for _, pi := range m.ParticipantIdentities {
+		if pi.Player.AccountID == s.AccountID || pi.Player.SummonerID == s.ID {
+			participantID = pi.ParticipantID
+			break
+		}
+	}
+	if participantID == 0 {
+		return false, false
+	}
+
+	for _, p := range m.Participants {
+		if p.ParticipantID == participantID {
+			return p.Stats.Win, true
+		}
+	}
+	return false, false
+}
+
+// staticNames maps static data IDs to their display names.
+type staticNames struct {
+	champions map[int64]string
+	spells    map[int64]string
+	perkPaths map[int64]string
+}
+
+// staticNameCache holds the names fetched for EnrichCurrentGame, keyed by lookup.
+type staticNameCache struct {
+	mu      sync.Mutex
+	names   map[string]map[int64]string
+	fetched map[string]time.Time
+}
+
+// staticNames returns the names from the Client's cache, fetching any which are
+// missing or older than maxAge. The cache is locked while fetching, so that
+// concurrent enrichments do not fetch the same data.
+func (e *enricher) staticNames(maxAge time.Duration) (staticNames, []error) {
+	cache := &e.client.staticNames
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if cache.names == nil {
+		cache.names = make(map[string]map[int64]string)
+		cache.fetched = make(map[string]time.Time)
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		errs    []error
+		fetched = make(map[string]map[int64]string)
+	)
+	now := time.Now()
+	lookup := func(name string, fetch func() (map[int64]string, *http.Response, error)) {
+		if fetched, ok := cache.fetched[name]; ok && now.Sub(fetched) < maxAge {
+			return
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var names map[int64]string
+			err := e.call(func() (resp *http.Response, err error) {
+				names, resp, err = fetch()
+				return resp, err
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, &EnrichError{Lookup: name, Err: err})
+				return
+			}
+			fetched[name] = names
+		}()
+	}
+
+	lookup("static champions", func() (map[int64]string, *http.Response, error) {
+		champions, resp, err := e.client.StaticData.Champions()
+		if err != nil {
+			return nil, resp, err
+		}
+		names := make(map[int64]string, len(champions.Data))
+		for _, c := range champions.Data {
+			names[int64(c.ID)] = c.Name
+		}
+		return names, resp, nil
+	})
+	lookup("static summoner spells", func() (map[int64]string, *http.Response, error) {
+		spells, resp, err := e.client.StaticData.SummonerSpells()
+		if err != nil {
+			return nil, resp, err
+		}
+		names := make(map[int64]string, len(spells.Data))
+		for _, s := range spells.Data {
+			names[int64(s.ID)] = s.Name
+		}
+		return names, resp, nil
+	})
+	lookup("static rune paths", func() (map[int64]string, *http.Response, error) {
+		paths, resp, err := e.client.StaticData.ReforgedRunePaths()
+		if err != nil {
+			return nil, resp, err
+		}
+		names := make(map[int64]string, len(paths))
+		for _, p := range paths {
+			names[int64(p.ID)] = p.Name
+		}
+		return names, resp, nil
+	})
+	wg.Wait()
+
+	for name, names := range fetched {
+		cache.names[name], cache.fetched[name] = names, now
+	}
+	// The cached maps are replaced rather than modified, so they can be shared.
+	return staticNames{
+		champions: cache.names["static champions"],
+		spells:    cache.names["static summoner spells"],
+		perkPaths: cache.names["static rune paths"],
+	}, errs
+}
+
+func (n staticNames) resolve(ep *EnrichedParticipant) {
+	ep.ChampionName = n.champions[ep.ChampionID]
+	ep.Spell1Name = n.spells[ep.Spell1ID]
+	ep.Spell2Name = n.spells[ep.Spell2ID]
+	ep.PerkStyleName = n.perkPaths[ep.Perks.PerkStyle]
+	ep.PerkSubStyleName = n.perkPaths[ep.Perks.PerkSubStyle]
+}
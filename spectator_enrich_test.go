@@ -0,0 +1,171 @@
+package ionia
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEnrichedCurrentGame(t *testing.T) {
+	client, mux, _, teardown := createTestServer()
+	defer teardown()
+
+	mux.HandleFunc("/lol/spectator/v3/active-games/by-summoner/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"gameId": 99,
+			"participants": [
+				{"summonerId": 1, "summonerName": "one", "championId": 17, "spell1Id": 4, "spell2Id": 14, "perks": {"perkStyle": 8100, "perkSubStyle": 8000}},
+				{"summonerId": 2, "summonerName": "two", "championId": 22},
+				{"summonerId": 0, "summonerName": "bot", "championId": 1, "bot": true}
+			]
+		}`)
+	})
+	mux.HandleFunc("/lol/league/v3/positions/by-summoner/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"queueType": "RANKED_SOLO_5x5", "tier": "GOLD"}]`)
+	})
+	mux.HandleFunc("/lol/league/v3/positions/by-summoner/2", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+	})
+	mux.HandleFunc("/lol/champion-mastery/v3/champion-masteries/by-summoner/1/by-champion/17", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"championId": 17, "championLevel": 7}`)
+	})
+	mux.HandleFunc("/lol/champion-mastery/v3/champion-masteries/by-summoner/2/by-champion/22", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"championId": 22, "championLevel": 3}`)
+	})
+	mux.HandleFunc("/lol/summoner/v3/summoners/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 1, "accountId": 101}`)
+	})
+	mux.HandleFunc("/lol/summoner/v3/summoners/2", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 2, "accountId": 102}`)
+	})
+	mux.HandleFunc("/lol/match/v3/matchlists/by-account/101/recent/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"matches": [{"gameId": 1001}, {"gameId": 1002}, {"gameId": 1003}]}`)
+	})
+	mux.HandleFunc("/lol/match/v3/matchlists/by-account/102/recent/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"matches": []}`)
+	})
+	for id, win := range map[int]bool{1001: true, 1002: false} {
+		win := win
+		mux.HandleFunc(fmt.Sprintf("/lol/match/v3/matches/%d", id), func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{
+				"participantIdentities": [{"participantId": 3, "player": {"accountId": 101, "summonerId": 1}}],
+				"participants": [{"participantId": 3, "stats": {"win": %t}}]
+			}`, win)
+		})
+	}
+	mux.HandleFunc("/lol/static-data/v3/champions", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": {"Annie": {"id": 1, "name": "Annie"}, "Teemo": {"id": 17, "name": "Teemo"}}}`)
+	})
+	mux.HandleFunc("/lol/static-data/v3/summoner-spells", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": {"SummonerFlash": {"id": 4, "name": "Flash"}, "SummonerDot": {"id": 14, "name": "Ignite"}}}`)
+	})
+	mux.HandleFunc("/lol/static-data/v3/reforged-rune-paths", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": 8100, "name": "Domination"}, {"id": 8000, "name": "Precision"}]`)
+	})
+
+	got, _, err := client.Spectator.EnrichedCurrentGame(1, func(o *EnrichOptions) {
+		o.RecentMatches = 3
+	})
+	if err != nil {
+		t.Fatalf("Spectator.EnrichedCurrentGame returned error: %v", err)
+	}
+	if len(got.Errors) != 0 {
+		t.Errorf("unexpected static data errors: %v", got.Errors)
+	}
+	if len(got.Participants) != 3 {
+		t.Fatalf("expected 3 participants, got %d", len(got.Participants))
+	}
+
+	one := got.Participants[0]
+	if one.ChampionName != "Teemo" || one.Spell1Name != "Flash" || one.Spell2Name != "Ignite" {
+		t.Errorf("unexpected names resolved: %q, %q, %q", one.ChampionName, one.Spell1Name, one.Spell2Name)
+	}
+	if one.PerkStyleName != "Domination" || one.PerkSubStyleName != "Precision" {
+		t.Errorf("unexpected perk names resolved: %q, %q", one.PerkStyleName, one.PerkSubStyleName)
+	}
+	if len(one.Positions) != 1 || one.Positions[0].Tier != "GOLD" {
+		t.Errorf("unexpected positions: %+v", one.Positions)
+	}
+	if one.Mastery == nil || one.Mastery.ChampionLevel != 7 {
+		t.Errorf("unexpected mastery: %+v", one.Mastery)
+	}
+	if one.RecentGames != 2 || one.RecentWins != 1 || one.RecentWinRate() != 0.5 {
+		t.Errorf("unexpected recent results: %d games, %d wins", one.RecentGames, one.RecentWins)
+	}
+	if len(one.Errors) != 1 {
+		t.Errorf("expected 1 error for the missing match, got %v", one.Errors)
+	}
+
+	two := got.Participants[1]
+	if two.Positions != nil {
+		t.Errorf("expected no positions, got %+v", two.Positions)
+	}
+	if two.Mastery == nil || two.Mastery.ChampionLevel != 3 {
+		t.Errorf("unexpected mastery: %+v", two.Mastery)
+	}
+	if len(two.Errors) != 1 {
+		t.Errorf("expected 1 error for the failed positions lookup, got %v", two.Errors)
+	}
+
+	bot := got.Participants[2]
+	if bot.ChampionName != "Annie" || bot.Mastery != nil || len(bot.Errors) != 0 {
+		t.Errorf("unexpected bot enrichment: %+v", bot)
+	}
+}
+
+func TestEnrichStaticDataCached(t *testing.T) {
+	client, mux, _, teardown := createTestServer()
+	defer teardown()
+
+	requests := make(map[string]int)
+	var mu sync.Mutex
+	static := func(body string, fail bool) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			requests[r.URL.Path]++
+			first := requests[r.URL.Path] == 1
+			mu.Unlock()
+			if fail && first {
+				http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprint(w, body)
+		}
+	}
+	mux.HandleFunc("/lol/static-data/v3/champions", static(`{"data": {"Teemo": {"id": 17, "name": "Teemo"}}}`, false))
+	mux.HandleFunc("/lol/static-data/v3/summoner-spells", static(`{"data": {"SummonerFlash": {"id": 4, "name": "Flash"}}}`, true))
+	mux.HandleFunc("/lol/static-data/v3/reforged-rune-paths", static(`[{"id": 8100, "name": "Domination"}]`, false))
+
+	game := &CurrentGameInfo{Participants: []CurrentGameParticipant{{ChampionID: 17, Spell1ID: 4, Bot: true}}}
+	first := client.Spectator.EnrichCurrentGame(game)
+	if len(first.Errors) != 1 || first.Participants[0].Spell1Name != "" {
+		t.Errorf("expected the summoner spells lookup to fail, got %v", first.Errors)
+	}
+	if first.Participants[0].ChampionName != "Teemo" {
+		t.Errorf("unexpected champion name %q", first.Participants[0].ChampionName)
+	}
+
+	// Only the lookup which failed is fetched again.
+	second := client.Spectator.EnrichCurrentGame(game)
+	if len(second.Errors) != 0 || second.Participants[0].Spell1Name != "Flash" || second.Participants[0].ChampionName != "Teemo" {
+		t.Errorf("unexpected second enrichment: %+v, %v", second.Participants[0], second.Errors)
+	}
+	client.Spectator.EnrichCurrentGame(game)
+	want := map[string]int{
+		"/lol/static-data/v3/champions":           1,
+		"/lol/static-data/v3/summoner-spells":     2,
+		"/lol/static-data/v3/reforged-rune-paths": 1,
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("unexpected static data requests: got %v, want %v", requests, want)
+	}
+
+	// Static data older than StaticDataMaxAge is fetched again.
+	client.Spectator.EnrichCurrentGame(game, func(o *EnrichOptions) { o.StaticDataMaxAge = time.Nanosecond })
+	if n := requests["/lol/static-data/v3/champions"]; n != 2 {
+		t.Errorf("expected champions to be fetched again, got %d requests", n)
+	}
+}
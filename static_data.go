@@ -1,6 +1,7 @@
 package ionia
 
 import (
+	"encoding/json"
 	"net/http"
 	"strconv"
 )
@@ -217,6 +218,42 @@ func (s *StaticDataService) Champions(opts ...StaticDataChampionsOption) (*Stati
 	return cl, resp, nil
 }
 
+// StreamChampions retrieves champions like Champions, decoding them one at a time and passing
+// each to fn, along with its key in Data, instead of holding them all in memory.
+// The returned list has no Data. If fn returns an error, decoding stops and it is returned.
+func (s *StaticDataService) StreamChampions(fn func(key string, champion *StaticChampionDTO) error, opts ...StaticDataChampionsOption) (*StaticChampionListDTO, *http.Response, error) {
+	options := &StaticDataChampionsOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	u, err := addOptions("lol/static-data/v3/champions", options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cl := &StaticChampionListDTO{}
+	resp, err := s.client.stream(req, cl, map[string]streamFunc{
+		"data": func(key string, dec *json.Decoder) error {
+			champion := &StaticChampionDTO{}
+			if err := dec.Decode(champion); err != nil {
+				return err
+			}
+			return fn(key, champion)
+		},
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return cl, resp, nil
+}
+
 // ChampionByID gets champion information by champion ID.
 func (s *StaticDataService) ChampionByID(championID int64, opts ...StaticDataChampionsOption) (*StaticChampionDTO, *http.Response, error) {
 	options := &StaticDataChampionsOptions{}
@@ -387,6 +424,42 @@ func (s *StaticDataService) Items(opts ...StaticDataItemsOption) (*ItemListDTO,
 	return il, resp, nil
 }
 
+// StreamItems retrieves items like Items, decoding them one at a time and passing
+// each to fn, along with its key in Data, instead of holding them all in memory.
+// The returned list has no Data. If fn returns an error, decoding stops and it is returned.
+func (s *StaticDataService) StreamItems(fn func(key string, item *ItemDTO) error, opts ...StaticDataItemsOption) (*ItemListDTO, *http.Response, error) {
+	options := &StaticDataItemsOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	u, err := addOptions("lol/static-data/v3/items", options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	il := &ItemListDTO{}
+	resp, err := s.client.stream(req, il, map[string]streamFunc{
+		"data": func(key string, dec *json.Decoder) error {
+			item := &ItemDTO{}
+			if err := dec.Decode(item); err != nil {
+				return err
+			}
+			return fn(key, item)
+		},
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return il, resp, nil
+}
+
 // ItemByID retrieves an item by ID.
 func (s *StaticDataService) ItemByID(itemID int64, opts ...StaticDataItemsOption) (*ItemDTO, *http.Response, error) {
 	options := &StaticDataItemsOptions{}
@@ -0,0 +1,127 @@
+package ionia
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// WithRawResponses returns a ClientOption which keeps the body of each 200 OK
+// response after it has been decoded, so that the raw JSON can be archived
+// alongside the typed DTO with RawMessage. Responses decoded by the Stream
+// methods are not kept, since that would hold them in memory.
+func WithRawResponses() ClientOption {
+	return func(c *Client) {
+		c.keepRaw = true
+	}
+}
+
+// RawMessage returns the raw JSON of a response returned by a Client created
+// with WithRawResponses. It can be called more than once.
+func RawMessage(resp *http.Response) (json.RawMessage, error) {
+	if resp == nil || resp.Body == nil {
+		return nil, fmt.Errorf("response has no body")
+	}
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+	return json.RawMessage(b), nil
+}
+
+// streamFunc decodes one element of a streamed array or object with dec.
+// For objects, key is the element's name; for arrays, it is "".
+type streamFunc func(key string, dec *json.Decoder) error
+
+// stream sends an API request like Do, decoding the members of the response
+// object named in streams one element at a time, and the other members into v.
+// Stream requests are never coalesced.
+func (c *Client) stream(req *http.Request, v interface{}, streams map[string]streamFunc) (*http.Response, error) {
+	return c.doDecode(req, true, func(r io.Reader) error {
+		return decodeStream(r, v, streams)
+	})
+}
+
+// decodeStream decodes the JSON object read from r, passing the elements of the
+// members named in streams to their streamFunc and decoding the rest into v.
+func decodeStream(r io.Reader, v interface{}, streams map[string]streamFunc) error {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	rest := make(map[string]json.RawMessage)
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		name, _ := t.(string)
+
+		fn, ok := streams[name]
+		if !ok {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return err
+			}
+			rest[name] = raw
+			continue
+		}
+
+		t, err = dec.Token()
+		if err != nil {
+			return err
+		}
+		switch t {
+		case json.Delim('['):
+			for dec.More() {
+				if err := fn("", dec); err != nil {
+					return err
+				}
+			}
+		case json.Delim('{'):
+			for dec.More() {
+				t, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				key, _ := t.(string)
+				if err := fn(key, dec); err != nil {
+					return err
+				}
+			}
+		case nil:
+			continue
+		default:
+			return fmt.Errorf("expected array or object for %q, got %v", name, t)
+		}
+		// Consume the closing delimiter.
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+	}
+
+	if v == nil {
+		return nil
+	}
+	b, err := json.Marshal(rest)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func expectDelim(dec *json.Decoder, d json.Delim) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if t != d {
+		return fmt.Errorf("expected %v, got %v", d, t)
+	}
+	return nil
+}
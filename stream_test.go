@@ -0,0 +1,146 @@
+package ionia
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStreamChampions(t *testing.T) {
+	client, mux, _, teardown := createTestServer()
+	defer teardown()
+
+	mux.HandleFunc("/lol/static-data/v3/champions", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(staticChampionsJSON)
+	})
+
+	data := make(map[string]StaticChampionDTO)
+	got, _, err := client.StaticData.StreamChampions(func(key string, c *StaticChampionDTO) error {
+		data[key] = *c
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StaticData.StreamChampions returned error: %v", err)
+	}
+	if !reflect.DeepEqual(data, wantStaticChampions.Data) {
+		t.Errorf("streamed champions = %+v, want %+v", data, wantStaticChampions.Data)
+	}
+	want := *wantStaticChampions
+	want.Data = nil
+	if !reflect.DeepEqual(got, &want) {
+		t.Errorf("StaticData.StreamChampions = %+v, want %+v", got, &want)
+	}
+
+	stop := errors.New("stop")
+	if _, _, err := client.StaticData.StreamChampions(func(string, *StaticChampionDTO) error { return stop }); err != stop {
+		t.Errorf("expected error from fn, got %v", err)
+	}
+}
+
+func TestStreamTimelineByID(t *testing.T) {
+	client, mux, _, teardown := createTestServer()
+	defer teardown()
+
+	mux.HandleFunc("/lol/match/v3/timelines/by-match/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"frames": [{"timestamp": 0}, {"timestamp": 60000}, {"timestamp": 120000}], "frameInterval": 60000}`)
+	})
+
+	var timestamps []int64
+	got, _, err := client.Match.StreamTimelineByID(1, func(f *MatchFrameDTO) error {
+		timestamps = append(timestamps, f.Timestamp)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Match.StreamTimelineByID returned error: %v", err)
+	}
+	if want := []int64{0, 60000, 120000}; !reflect.DeepEqual(timestamps, want) {
+		t.Errorf("streamed timestamps = %v, want %v", timestamps, want)
+	}
+	if want := (&MatchTimelineDTO{FrameInterval: 60000}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Match.StreamTimelineByID = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeStreamErrors(t *testing.T) {
+	tt := []struct {
+		name string
+		body string
+	}{
+		{"Not Object", `[]`},
+		{"Not Array", `{"frames": 1}`},
+		{"Truncated", `{"frames": [{"timestamp": 0}`},
+	}
+
+	for _, tc := range tt {
+		err := decodeStream(strings.NewReader(tc.body), nil, map[string]streamFunc{
+			"frames": func(_ string, dec *json.Decoder) error { return dec.Decode(&MatchFrameDTO{}) },
+		})
+		if err == nil {
+			t.Errorf("%s: expected error", tc.name)
+		}
+	}
+}
+
+func TestRawResponses(t *testing.T) {
+	client, mux, _, teardown := createTestServer()
+	defer teardown()
+	WithRawResponses()(client)
+
+	const body = `{"id": 1, "name": "Faker", "unknown": true}`
+	mux.HandleFunc("/lol/summoner/v3/summoners/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	})
+
+	s, resp, err := client.Summoner.BySummonerID(1)
+	if err != nil {
+		t.Fatalf("Summoner.BySummonerID returned error: %v", err)
+	}
+	if s.Name != "Faker" {
+		t.Errorf("unexpected summoner: %+v", s)
+	}
+	for i := 0; i < 2; i++ {
+		raw, err := RawMessage(resp)
+		if err != nil || string(raw) != body {
+			t.Errorf("RawMessage = %s, %v; want %s", raw, err, body)
+		}
+	}
+}
+
+func TestStreamNotCoalesced(t *testing.T) {
+	client, mux, _, teardown := createTestServer()
+	defer teardown()
+	WithCoalescing()(client)
+
+	first := make(chan struct{})
+	streamed := make(chan bool, 1)
+	mux.HandleFunc("/lol/match/v3/timelines/by-match/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"frames": [{"timestamp": 0},`)
+		w.(http.Flusher).Flush()
+		// A coalesced request reads the whole body before decoding any of it.
+		select {
+		case <-first:
+			streamed <- true
+		case <-time.After(time.Second):
+			streamed <- false
+		}
+		fmt.Fprint(w, `{"timestamp": 60000}], "frameInterval": 60000}`)
+	})
+
+	_, _, err := client.Match.StreamTimelineByID(1, func(f *MatchFrameDTO) error {
+		if f.Timestamp == 0 {
+			close(first)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Match.StreamTimelineByID returned error: %v", err)
+	}
+	if !<-streamed {
+		t.Errorf("expected frames to be decoded while the response was being read")
+	}
+}
@@ -1,6 +1,84 @@
 package ionia
 
-import "net/http"
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// MapType is the map a tournament game is played on.
+type MapType string
+
+// Legal values for MapType.
+const (
+	MapTypeSummonersRift   MapType = "SUMMONERS_RIFT"
+	MapTypeTwistedTreeline MapType = "TWISTED_TREELINE"
+	MapTypeHowlingAbyss    MapType = "HOWLING_ABYSS"
+)
+
+// PickType is the champion selection mode of a tournament game.
+type PickType string
+
+// Legal values for PickType.
+const (
+	PickTypeBlindPick       PickType = "BLIND_PICK"
+	PickTypeDraftMode       PickType = "DRAFT_MODE"
+	PickTypeAllRandom       PickType = "ALL_RANDOM"
+	PickTypeTournamentDraft PickType = "TOURNAMENT_DRAFT"
+)
+
+// SpectatorType determines who may spectate a tournament game.
+type SpectatorType string
+
+// Legal values for SpectatorType.
+const (
+	SpectatorTypeNone      SpectatorType = "NONE"
+	SpectatorTypeLobbyOnly SpectatorType = "LOBBYONLY"
+	SpectatorTypeAll       SpectatorType = "ALL"
+)
+
+// Valid reports whether m is a legal map type.
+func (m MapType) Valid() bool {
+	return m == MapTypeSummonersRift || m == MapTypeTwistedTreeline || m == MapTypeHowlingAbyss
+}
+
+// Valid reports whether p is a legal pick type.
+func (p PickType) Valid() bool {
+	return p == PickTypeBlindPick || p == PickTypeDraftMode || p == PickTypeAllRandom || p == PickTypeTournamentDraft
+}
+
+// Valid reports whether s is a legal spectator type.
+func (s SpectatorType) Valid() bool {
+	return s == SpectatorTypeNone || s == SpectatorTypeLobbyOnly || s == SpectatorTypeAll
+}
+
+// ValidationError is returned when a request or callback body fails validation.
+// It lists every problem found, rather than only the first.
+type ValidationError struct {
+	// What was validated (e.g. tournament code).
+	Subject string
+
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid " + e.Subject + ": " + strings.Join(e.Problems, ", ")
+}
+
+// newValidationError returns a *ValidationError for the problems, or nil if there are none.
+func newValidationError(subject string, problems []string) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Subject: subject, Problems: problems}
+}
+
+// Limits on the values accepted by the tournament code endpoints.
+const (
+	MinTeamSize        = 1
+	MaxTeamSize        = 5
+	MaxTournamentCodes = 1000
+)
 
 // TournamentService represents the Tournament-Stub-V3 API methods.
 // https://developer.riotgames.com/api-methods/#tournament-v3
@@ -18,7 +96,7 @@ type TournamentCode struct {
 	AllowedSummonerIDs []int `json:"allowedSummonerIds"`
 
 	// The map type of the game. (Legal values: SUMMONERS_RIFT, TWISTED_TREELINE, HOWLING_ABYSS)
-	MapType string `json:"mapType"`
+	MapType MapType `json:"mapType"`
 
 	// Optional string that may contain any data in any format, if specified at all.
 	// Used to denote any custom information about the game.
@@ -26,15 +104,57 @@ type TournamentCode struct {
 
 	// The pick type of the game.
 	// (Legal values: BLIND_PICK, DRAFT_MODE, ALL_RANDOM, TOURNAMENT_DRAFT)
-	PickType string `json:"pickType"`
+	PickType PickType `json:"pickType"`
 
 	// The spectator type of the game. (Legal values: NONE, LOBBYONLY, ALL)
-	SpectatorType string `json:"spectatorType"`
+	SpectatorType SpectatorType `json:"spectatorType"`
 
 	// The team size of the game. Valid values are 1-5.
 	TeamSize int `json:"teamSize"`
 }
 
+// Validate checks every field of tc against the values accepted by Riot, and
+// returns a ValidationError listing all of the problems found.
+func (tc *TournamentCode) Validate() error {
+	return newValidationError("tournament code", tc.problems())
+}
+
+func (tc *TournamentCode) problems() []string {
+	var problems []string
+	if tc.TeamSize < MinTeamSize || tc.TeamSize > MaxTeamSize {
+		problems = append(problems, fmt.Sprintf("teamSize must be between %d and %d, got %d", MinTeamSize, MaxTeamSize, tc.TeamSize))
+	}
+	if !tc.MapType.Valid() {
+		problems = append(problems, fmt.Sprintf("illegal mapType %q", tc.MapType))
+	}
+	if !tc.PickType.Valid() {
+		problems = append(problems, fmt.Sprintf("illegal pickType %q", tc.PickType))
+	}
+	if !tc.SpectatorType.Valid() {
+		problems = append(problems, fmt.Sprintf("illegal spectatorType %q", tc.SpectatorType))
+	}
+	problems = append(problems, validateAllowedSummoners(tc.AllowedSummonerIDs)...)
+	if tc.TeamSize >= MinTeamSize && len(tc.AllowedSummonerIDs) > 2*tc.TeamSize {
+		problems = append(problems, fmt.Sprintf("%d allowedSummonerIds do not fit in two teams of %d", len(tc.AllowedSummonerIDs), tc.TeamSize))
+	}
+	return problems
+}
+
+// validateAllowedSummoners checks that the IDs are positive and unique.
+func validateAllowedSummoners(ids []int) []string {
+	var problems []string
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if id <= 0 {
+			problems = append(problems, fmt.Sprintf("illegal allowedSummonerIds entry %d", id))
+		} else if seen[id] {
+			problems = append(problems, fmt.Sprintf("duplicate allowedSummonerIds entry %d", id))
+		}
+		seen[id] = true
+	}
+	return problems
+}
+
 // TournamentCodesOptions specifies the optional parameters for the Tournament Stub codes service method.
 type TournamentCodesOptions struct {
 	// The number of codes to create (max 1000).
@@ -44,12 +164,36 @@ type TournamentCodesOptions struct {
 	TournamentID int64 `url:"tournamentId"`
 }
 
+// Validate checks that the number of codes requested is no more than MaxTournamentCodes.
+// A Count of zero is sent as is, and creates a single code.
+func (o *TournamentCodesOptions) Validate() error {
+	return newValidationError("tournament code options", o.problems())
+}
+
+func (o *TournamentCodesOptions) problems() []string {
+	var problems []string
+	if o.Count < 0 || o.Count > MaxTournamentCodes {
+		problems = append(problems, fmt.Sprintf("count must be between 0 and %d, where 0 creates a single code, got %d", MaxTournamentCodes, o.Count))
+	}
+	return problems
+}
+
+// validateCodes validates the parameters of a request to create tournament codes,
+// returning a single ValidationError for the problems with both.
+func validateCodes(tc *TournamentCode, options *TournamentCodesOptions) error {
+	return newValidationError("tournament code request", append(tc.problems(), options.problems()...))
+}
+
 // Codes creates a tournament code for the given tournament.
+// The TournamentCode and options are validated before the request is sent.
 func (t *TournamentService) Codes(tc *TournamentCode, opts ...TournamentCodesOption) ([]string, *http.Response, error) {
 	options := &TournamentCodesOptions{}
 	for _, o := range opts {
 		o(options)
 	}
+	if err := validateCodes(tc, options); err != nil {
+		return nil, nil, err
+	}
 
 	u := "lol/tournament/v3/codes"
 	u, err := addOptions(u, options)
@@ -73,14 +217,37 @@ func (t *TournamentService) Codes(tc *TournamentCode, opts ...TournamentCodesOpt
 
 // TournamentCodeUpdate specifies the optional body parameters for the Tournament code update service method.
 type TournamentCodeUpdate struct {
-	AllowedSummonerIDs []int  `json:"allowedSummonerIds"`
-	MapType            string `json:"mapType"`
-	PickType           string `json:"pickType"`
-	SpectatorType      string `json:"spectatorType"`
+	AllowedSummonerIDs []int         `json:"allowedSummonerIds"`
+	MapType            MapType       `json:"mapType"`
+	PickType           PickType      `json:"pickType"`
+	SpectatorType      SpectatorType `json:"spectatorType"`
+}
+
+// Validate checks the fields of tcu which are set against the values accepted by Riot,
+// and returns a ValidationError listing all of the problems found. The update does
+// not include the team size, so allowed summoners are only checked for duplicates.
+func (tcu *TournamentCodeUpdate) Validate() error {
+	var problems []string
+	if tcu.MapType != "" && !tcu.MapType.Valid() {
+		problems = append(problems, fmt.Sprintf("illegal mapType %q", tcu.MapType))
+	}
+	if tcu.PickType != "" && !tcu.PickType.Valid() {
+		problems = append(problems, fmt.Sprintf("illegal pickType %q", tcu.PickType))
+	}
+	if tcu.SpectatorType != "" && !tcu.SpectatorType.Valid() {
+		problems = append(problems, fmt.Sprintf("illegal spectatorType %q", tcu.SpectatorType))
+	}
+	problems = append(problems, validateAllowedSummoners(tcu.AllowedSummonerIDs)...)
+	return newValidationError("tournament code update", problems)
 }
 
 // UpdateTournament updates the pick type, map, spectator type, or allowed summoners for the given code.
+// The update is validated before the request is sent.
 func (t *TournamentService) UpdateTournament(tournamentCode string, tcu *TournamentCodeUpdate) (*http.Response, error) {
+	if err := tcu.Validate(); err != nil {
+		return nil, err
+	}
+
 	req, err := t.client.NewRequest(http.MethodPut, "lol/tournament/v3/codes/"+tournamentCode, tcu)
 	if err != nil {
 		return nil, err
@@ -175,7 +342,7 @@ func (t *TournamentService) Provider(pr *ProviderRegistration) (*int, *http.Resp
 		return nil, nil, err
 	}
 
-	var id *int
+	id := new(int)
 	resp, err := t.client.Do(req, id)
 	if err != nil {
 		return nil, resp, err
@@ -200,7 +367,7 @@ func (t *TournamentService) Tournament(tr *TournamentRegistration) (*int, *http.
 		return nil, nil, err
 	}
 
-	var id *int
+	id := new(int)
 	resp, err := t.client.Do(req, id)
 	if err != nil {
 		return nil, resp, err
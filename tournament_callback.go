@@ -0,0 +1,98 @@
+package ionia
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// maxCallbackBodySize limits the size of tournament callback bodies read by TournamentCallbackHandler.
+const maxCallbackBodySize = 1 << 20
+
+// TournamentCallback contains the game result which Riot POSTs to the URL
+// given in a ProviderRegistration when a tournament game finishes.
+type TournamentCallback struct {
+	// Time at which the game started, specified as epoch milliseconds.
+	StartTime int64 `json:"startTime"`
+
+	// The tournament code the game was played with.
+	ShortCode string `json:"shortCode"`
+
+	// The metadata given when the tournament code was created.
+	MetaData string `json:"metaData"`
+
+	GameID   int64  `json:"gameId"`
+	GameName string `json:"gameName"`
+	GameType string `json:"gameType"`
+	GameMap  int    `json:"gameMap"`
+	GameMode string `json:"gameMode"`
+
+	// The platform the game was played on (e.g. NA1).
+	Region string `json:"region"`
+
+	WinningTeam []TournamentCallbackPlayer `json:"winningTeam"`
+	LosingTeam  []TournamentCallbackPlayer `json:"losingTeam"`
+}
+
+// TournamentCallbackPlayer is a player on one of the teams of a TournamentCallback.
+type TournamentCallbackPlayer struct {
+	SummonerName string `json:"summonerName"`
+	SummonerID   int64  `json:"summonerId"`
+}
+
+// Validate reports whether the callback contains the fields required to identify the game.
+func (cb *TournamentCallback) Validate() error {
+	var problems []string
+	if cb.ShortCode == "" {
+		problems = append(problems, "shortCode is missing")
+	}
+	if cb.GameID <= 0 {
+		problems = append(problems, "gameId is missing")
+	}
+	if cb.Region == "" {
+		problems = append(problems, "region is missing")
+	}
+	if cb.StartTime <= 0 {
+		problems = append(problems, "startTime is missing")
+	}
+	if len(cb.WinningTeam) == 0 {
+		problems = append(problems, "winningTeam is empty")
+	}
+	if len(cb.LosingTeam) == 0 {
+		problems = append(problems, "losingTeam is empty")
+	}
+
+	return newValidationError("tournament callback", problems)
+}
+
+// TournamentCallbackHandler is an http.Handler which receives tournament game results.
+//
+// The handler only accepts POST requests. Bodies which cannot be decoded or fail
+// validation are answered with 400 Bad Request. Valid callbacks are passed to the
+// function, and an error returned from it is answered with 500 Internal Server Error
+// so that Riot retries the callback.
+type TournamentCallbackHandler func(r *http.Request, cb *TournamentCallback) error
+
+// ServeHTTP implements http.Handler.
+func (h TournamentCallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	cb := &TournamentCallback{}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxCallbackBodySize)).Decode(cb); err != nil {
+		http.Error(w, "invalid tournament callback: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := cb.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h(r, cb); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,78 @@
+package ionia
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var tournamentCallbackJSON = `{
+	"startTime": 1234567890000,
+	"winningTeam": [{"summonerName": "Winner", "summonerId": 1}],
+	"losingTeam": [{"summonerName": "Loser", "summonerId": 2}],
+	"shortCode": "NA1234a-1a23b456-a1b2-1abc-ab12-1234567890ab",
+	"metaData": "{\"title\":\"Game 42 - Finals\"}",
+	"gameId": 1234567890,
+	"gameName": "a123bc45-ab1c-1a23-ab12-12345a67b89c",
+	"gameType": "Practice",
+	"gameMap": 11,
+	"gameMode": "CLASSIC",
+	"region": "NA1"
+}`
+
+var wantTournamentCallback = &TournamentCallback{
+	StartTime:   1234567890000,
+	WinningTeam: []TournamentCallbackPlayer{{SummonerName: "Winner", SummonerID: 1}},
+	LosingTeam:  []TournamentCallbackPlayer{{SummonerName: "Loser", SummonerID: 2}},
+	ShortCode:   "NA1234a-1a23b456-a1b2-1abc-ab12-1234567890ab",
+	MetaData:    `{"title":"Game 42 - Finals"}`,
+	GameID:      1234567890,
+	GameName:    "a123bc45-ab1c-1a23-ab12-12345a67b89c",
+	GameType:    "Practice",
+	GameMap:     11,
+	GameMode:    "CLASSIC",
+	Region:      "NA1",
+}
+
+func TestTournamentCallbackHandler(t *testing.T) {
+	tt := []struct {
+		name        string
+		method      string
+		body        string
+		callbackErr error
+		wantStatus  int
+		wantCalled  bool
+	}{
+		{name: "Valid", method: http.MethodPost, body: tournamentCallbackJSON, wantStatus: http.StatusOK, wantCalled: true},
+		{name: "Callback Error", method: http.MethodPost, body: tournamentCallbackJSON, callbackErr: errors.New("db down"), wantStatus: http.StatusInternalServerError, wantCalled: true},
+		{name: "Wrong Method", method: http.MethodGet, wantStatus: http.StatusMethodNotAllowed},
+		{name: "Malformed Body", method: http.MethodPost, body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "Missing Fields", method: http.MethodPost, body: `{"gameId": 1}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var got *TournamentCallback
+			h := TournamentCallbackHandler(func(r *http.Request, cb *TournamentCallback) error {
+				got = cb
+				return tc.callbackErr
+			})
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(tc.method, "/callback", strings.NewReader(tc.body)))
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("unexpected status: got %d, want %d", rec.Code, tc.wantStatus)
+			}
+			if called := got != nil; called != tc.wantCalled {
+				t.Fatalf("callback called = %v, want %v", called, tc.wantCalled)
+			}
+			if tc.wantCalled && !reflect.DeepEqual(got, wantTournamentCallback) {
+				t.Errorf("callback received %+v, want %+v", got, wantTournamentCallback)
+			}
+		})
+	}
+}
@@ -0,0 +1,383 @@
+package ionia
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+)
+
+// CodeState is the lifecycle state of a tournament code.
+type CodeState string
+
+// Tournament code states tracked by the TournamentManager.
+const (
+	// CodeCreated means the code has been minted but no lobby has been created with it.
+	CodeCreated CodeState = "CREATED"
+
+	// CodeInLobby means a lobby has been created with the code.
+	CodeInLobby CodeState = "LOBBY"
+
+	// CodeInChampSelect means the lobby has started champion select.
+	CodeInChampSelect CodeState = "CHAMP_SELECT"
+
+	// CodeInGame means the game has been allocated to a server.
+	CodeInGame CodeState = "IN_GAME"
+
+	// CodeFinished means at least one match played with the code is in match history.
+	CodeFinished CodeState = "FINISHED"
+)
+
+// Lobby event types which move a tournament code to a new state.
+var lobbyEventStates = map[string]CodeState{
+	"PracticeGameCreatedEvent":   CodeInLobby,
+	"PlayerJoinedGameEvent":      CodeInLobby,
+	"ChampSelectStartedEvent":    CodeInChampSelect,
+	"GameAllocationStartedEvent": CodeInChampSelect,
+	"GameAllocatedToLsmEvent":    CodeInGame,
+}
+
+// ManagedCode is a tournament code tracked by the TournamentManager.
+type ManagedCode struct {
+	Code     string    `json:"code"`
+	Metadata string    `json:"metadata"`
+	State    CodeState `json:"state"`
+
+	// IDs of the matches played with the code, once they are in match history.
+	MatchIDs []int64 `json:"matchIds,omitempty"`
+}
+
+// TournamentState is the state persisted by the TournamentManager.
+type TournamentState struct {
+	ProviderID   int                     `json:"providerId"`
+	TournamentID int                     `json:"tournamentId"`
+	Codes        map[string]*ManagedCode `json:"codes"`
+}
+
+// TournamentStore persists the state of a TournamentManager between runs.
+type TournamentStore interface {
+	// Load returns the saved state, or nil if nothing has been saved yet.
+	Load() (*TournamentState, error)
+	Save(*TournamentState) error
+}
+
+// FileTournamentStore is a TournamentStore which saves state as JSON to a file.
+type FileTournamentStore struct {
+	Path string
+}
+
+// Load implements TournamentStore.
+func (f *FileTournamentStore) Load() (*TournamentState, error) {
+	b, err := ioutil.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s := &TournamentState{}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Save implements TournamentStore. The file is replaced atomically.
+func (f *FileTournamentStore) Save(s *TournamentState) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := f.Path + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.Path)
+}
+
+// TournamentManager registers a tournament provider and tournament once, mints
+// tournament codes and follows each code through the lobby until its matches
+// are in match history.
+type TournamentManager struct {
+	client *Client
+	store  TournamentStore
+
+	mu    sync.Mutex
+	state *TournamentState
+}
+
+// NewTournamentManager creates a manager, restoring any state saved in store.
+// If store is nil, state is only kept in memory.
+func NewTournamentManager(c *Client, store TournamentStore) (*TournamentManager, error) {
+	m := &TournamentManager{
+		client: c,
+		store:  store,
+	}
+
+	if store != nil {
+		s, err := store.Load()
+		if err != nil {
+			return nil, err
+		}
+		m.state = s
+	}
+	if m.state == nil {
+		m.state = &TournamentState{}
+	}
+	if m.state.Codes == nil {
+		m.state.Codes = make(map[string]*ManagedCode)
+	}
+
+	return m, nil
+}
+
+// ProviderID returns the registered provider ID, or 0 if none has been registered.
+func (m *TournamentManager) ProviderID() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.state.ProviderID
+}
+
+// TournamentID returns the registered tournament ID, or 0 if none has been registered.
+func (m *TournamentManager) TournamentID() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.state.TournamentID
+}
+
+// Register registers the provider and tournament, unless they have already been registered.
+// The ProviderID of tr is filled in from the registered provider.
+func (m *TournamentManager) Register(pr *ProviderRegistration, tr *TournamentRegistration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.state.ProviderID == 0 {
+		id, resp, err := m.client.Tournament.Provider(pr)
+		if err = checkResponse(resp, err); err != nil {
+			return fmt.Errorf("registering provider: %v", err)
+		}
+		m.state.ProviderID = *id
+		if err := m.save(); err != nil {
+			return err
+		}
+	}
+
+	if m.state.TournamentID == 0 {
+		reg := *tr
+		reg.ProviderID = m.state.ProviderID
+		id, resp, err := m.client.Tournament.Tournament(&reg)
+		if err = checkResponse(resp, err); err != nil {
+			return fmt.Errorf("registering tournament: %v", err)
+		}
+		m.state.TournamentID = *id
+		if err := m.save(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CreateCodes mints count codes which share the metadata of tc.
+func (m *TournamentManager) CreateCodes(tc *TournamentCode, count int) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	codes, err := m.createCodes(tc, count)
+	if err != nil {
+		return nil, err
+	}
+	return codes, m.save()
+}
+
+// CreateMatchCodes mints one code per entry of metadata, using the entry as the code's
+// metadata. This costs one request per code, so that each match can carry its own metadata.
+func (m *TournamentManager) CreateMatchCodes(tc *TournamentCode, metadata []string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var all []string
+	for _, md := range metadata {
+		c := *tc
+		c.Metadata = md
+		codes, err := m.createCodes(&c, 1)
+		if err != nil {
+			// Keep the codes minted so far.
+			if serr := m.save(); serr != nil {
+				return all, serr
+			}
+			return all, err
+		}
+		all = append(all, codes...)
+	}
+	return all, m.save()
+}
+
+func (m *TournamentManager) createCodes(tc *TournamentCode, count int) ([]string, error) {
+	if m.state.TournamentID == 0 {
+		return nil, fmt.Errorf("tournament has not been registered")
+	}
+
+	codes, resp, err := m.client.Tournament.Codes(tc, func(o *TournamentCodesOptions) {
+		o.Count = count
+		o.TournamentID = int64(m.state.TournamentID)
+	})
+	if err = checkResponse(resp, err); err != nil {
+		return nil, fmt.Errorf("creating codes: %v", err)
+	}
+
+	for _, c := range codes {
+		m.state.Codes[c] = &ManagedCode{
+			Code:     c,
+			Metadata: tc.Metadata,
+			State:    CodeCreated,
+		}
+	}
+	return codes, nil
+}
+
+// Update changes the settings of a code.
+func (m *TournamentManager) Update(code string, tcu *TournamentCodeUpdate) error {
+	resp, err := m.client.Tournament.UpdateTournament(code, tcu)
+	return checkResponse(resp, err)
+}
+
+// Code returns a copy of the tracked state of a code.
+// The second return value is false if the code is not tracked.
+func (m *TournamentManager) Code(code string) (ManagedCode, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mc, ok := m.state.Codes[code]
+	if !ok {
+		return ManagedCode{}, false
+	}
+	return copyManagedCode(mc), true
+}
+
+// Codes returns a copy of every tracked code, ordered by code.
+func (m *TournamentManager) Codes() []ManagedCode {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	codes := make([]ManagedCode, 0, len(m.state.Codes))
+	for _, mc := range m.state.Codes {
+		codes = append(codes, copyManagedCode(mc))
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i].Code < codes[j].Code })
+	return codes
+}
+
+func copyManagedCode(mc *ManagedCode) ManagedCode {
+	c := *mc
+	c.MatchIDs = append([]int64(nil), mc.MatchIDs...)
+	return c
+}
+
+// Refresh updates the state of a code from its lobby events, and looks up its
+// matches once the game has been allocated to a server.
+func (m *TournamentManager) Refresh(code string) (CodeState, error) {
+	m.mu.Lock()
+	mc, ok := m.state.Codes[code]
+	var state CodeState
+	if ok {
+		state = mc.State
+	}
+	m.mu.Unlock()
+	if !ok {
+		return "", fmt.Errorf("tournament code %q is not tracked", code)
+	}
+
+	if state != CodeInGame && state != CodeFinished {
+		events, resp, err := m.client.Tournament.LobbyEvents(code)
+		if err = checkResponse(resp, err); err != nil {
+			return state, fmt.Errorf("retrieving lobby events: %v", err)
+		}
+		for _, e := range events.EventList {
+			if s, ok := lobbyEventStates[e.EventType]; ok && codeStateOrder(s) > codeStateOrder(state) {
+				state = s
+			}
+		}
+	}
+
+	var matchIDs []int64
+	if state == CodeInGame || state == CodeFinished {
+		ids, resp, err := m.client.Match.MatchIDsByTournamentCode(code)
+		// A 404 means no match has reached match history yet.
+		if resp == nil || resp.StatusCode != http.StatusNotFound {
+			if err = checkResponse(resp, err); err != nil {
+				return state, fmt.Errorf("retrieving match IDs: %v", err)
+			}
+		}
+		if len(ids) > 0 {
+			state = CodeFinished
+			matchIDs = ids
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	mc.State = state
+	if matchIDs != nil {
+		mc.MatchIDs = matchIDs
+	}
+	return state, m.save()
+}
+
+// RefreshAll refreshes every code which is not yet finished.
+// It stops at the first error.
+func (m *TournamentManager) RefreshAll() error {
+	for _, mc := range m.Codes() {
+		if mc.State == CodeFinished {
+			continue
+		}
+		if _, err := m.Refresh(mc.Code); err != nil {
+			return fmt.Errorf("refreshing %s: %v", mc.Code, err)
+		}
+	}
+	return nil
+}
+
+// Matches retrieves the finished matches played with a code.
+func (m *TournamentManager) Matches(code string) ([]*MatchDTO, error) {
+	mc, ok := m.Code(code)
+	if !ok {
+		return nil, fmt.Errorf("tournament code %q is not tracked", code)
+	}
+
+	matches := make([]*MatchDTO, 0, len(mc.MatchIDs))
+	for _, id := range mc.MatchIDs {
+		match, resp, err := m.client.Match.MatchByIDAndTournamentCode(id, code)
+		if err = checkResponse(resp, err); err != nil {
+			return matches, fmt.Errorf("retrieving match %d: %v", id, err)
+		}
+		matches = append(matches, match)
+	}
+	return matches, nil
+}
+
+func (m *TournamentManager) save() error {
+	if m.store == nil {
+		return nil
+	}
+	return m.store.Save(m.state)
+}
+
+func codeStateOrder(s CodeState) int {
+	switch s {
+	case CodeInLobby:
+		return 1
+	case CodeInChampSelect:
+		return 2
+	case CodeInGame:
+		return 3
+	case CodeFinished:
+		return 4
+	}
+	return 0
+}
@@ -0,0 +1,109 @@
+package ionia
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestTournamentManager(t *testing.T) {
+	client, mux, _, teardown := createTestServer()
+	defer teardown()
+
+	registrations := 0
+	mux.HandleFunc("/lol/tournament/v3/providers", func(w http.ResponseWriter, r *http.Request) {
+		registrations++
+		fmt.Fprint(w, `10`)
+	})
+	mux.HandleFunc("/lol/tournament/v3/tournaments", func(w http.ResponseWriter, r *http.Request) {
+		registrations++
+		fmt.Fprint(w, `20`)
+	})
+	mux.HandleFunc("/lol/tournament/v3/codes", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("tournamentId"); got != "20" {
+			t.Errorf("unexpected tournamentId: %s", got)
+		}
+		fmt.Fprint(w, `["NA-CODE-1"]`)
+	})
+	inGame := false
+	mux.HandleFunc("/lol/tournament/v3/lobby-events/by-code/NA-CODE-1", func(w http.ResponseWriter, r *http.Request) {
+		if inGame {
+			fmt.Fprint(w, `{"eventList": [{"eventType": "PracticeGameCreatedEvent"}, {"eventType": "ChampSelectStartedEvent"}, {"eventType": "GameAllocatedToLsmEvent"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"eventList": [{"eventType": "PracticeGameCreatedEvent"}, {"eventType": "PlayerJoinedGameEvent"}]}`)
+	})
+	mux.HandleFunc("/lol/match/v3/matches/by-tournament-code/NA-CODE-1/ids", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[555]`)
+	})
+	mux.HandleFunc("/lol/match/v3/matches/555/by-tournament-code/NA-CODE-1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"gameId": 555}`)
+	})
+
+	dir, err := ioutil.TempDir("", "ionia-tournament")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir returned error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	store := &FileTournamentStore{Path: filepath.Join(dir, "state.json")}
+
+	m, err := NewTournamentManager(client, store)
+	if err != nil {
+		t.Fatalf("NewTournamentManager returned error: %v", err)
+	}
+	if err := m.Register(&ProviderRegistration{Region: "NA"}, &TournamentRegistration{Name: "test"}); err != nil {
+		t.Fatalf("Register returned error: %v", err)
+	}
+	codes, err := m.CreateMatchCodes(&TournamentCode{
+		MapType:       MapTypeSummonersRift,
+		PickType:      PickTypeTournamentDraft,
+		SpectatorType: SpectatorTypeAll,
+		TeamSize:      5,
+	}, []string{"round 1"})
+	if err != nil {
+		t.Fatalf("CreateMatchCodes returned error: %v", err)
+	}
+	if want := []string{"NA-CODE-1"}; !reflect.DeepEqual(codes, want) {
+		t.Errorf("CreateMatchCodes = %v, want %v", codes, want)
+	}
+
+	if state, err := m.Refresh("NA-CODE-1"); err != nil || state != CodeInLobby {
+		t.Errorf("Refresh = %v, %v, want %v", state, err, CodeInLobby)
+	}
+	inGame = true
+	if state, err := m.Refresh("NA-CODE-1"); err != nil || state != CodeFinished {
+		t.Errorf("Refresh = %v, %v, want %v", state, err, CodeFinished)
+	}
+
+	// A new manager restores the saved state without registering again.
+	m, err = NewTournamentManager(client, store)
+	if err != nil {
+		t.Fatalf("NewTournamentManager returned error: %v", err)
+	}
+	if err := m.Register(&ProviderRegistration{Region: "NA"}, &TournamentRegistration{Name: "test"}); err != nil {
+		t.Fatalf("Register returned error: %v", err)
+	}
+	if registrations != 2 {
+		t.Errorf("expected provider and tournament to be registered once, got %d registrations", registrations)
+	}
+	if m.ProviderID() != 10 || m.TournamentID() != 20 {
+		t.Errorf("unexpected IDs restored: provider %d, tournament %d", m.ProviderID(), m.TournamentID())
+	}
+
+	want := ManagedCode{Code: "NA-CODE-1", Metadata: "round 1", State: CodeFinished, MatchIDs: []int64{555}}
+	if got, ok := m.Code("NA-CODE-1"); !ok || !reflect.DeepEqual(got, want) {
+		t.Errorf("Code = %+v, want %+v", got, want)
+	}
+
+	matches, err := m.Matches("NA-CODE-1")
+	if err != nil {
+		t.Fatalf("Matches returned error: %v", err)
+	}
+	if len(matches) != 1 || matches[0].GameID != 555 {
+		t.Errorf("unexpected matches: %+v", matches)
+	}
+}
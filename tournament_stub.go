@@ -7,11 +7,15 @@ import "net/http"
 type TournamentStubService service
 
 // Codes creates a mock tournament code for the given tournament.
+// The TournamentCode and options are validated before the request is sent.
 func (t *TournamentStubService) Codes(tc *TournamentCode, opts ...TournamentCodesOption) ([]string, *http.Response, error) {
 	options := &TournamentCodesOptions{}
 	for _, o := range opts {
 		o(options)
 	}
+	if err := validateCodes(tc, options); err != nil {
+		return nil, nil, err
+	}
 
 	u := "lol/tournament-stub/v3/codes"
 	u, err := addOptions(u, options)
@@ -56,7 +60,7 @@ func (t *TournamentStubService) Provider(pr *ProviderRegistration) (*int, *http.
 		return nil, nil, err
 	}
 
-	var id *int
+	id := new(int)
 	resp, err := t.client.Do(req, id)
 	if err != nil {
 		return nil, resp, err
@@ -72,7 +76,7 @@ func (t *TournamentStubService) Tournament(tr *TournamentRegistration) (*int, *h
 		return nil, nil, err
 	}
 
-	var id *int
+	id := new(int)
 	resp, err := t.client.Do(req, id)
 	if err != nil {
 		return nil, resp, err
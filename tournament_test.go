@@ -0,0 +1,163 @@
+package ionia
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestTournamentCodeValidate(t *testing.T) {
+	tt := []struct {
+		name string
+		tc   TournamentCode
+		want []string
+	}{
+		{
+			name: "Valid",
+			tc:   TournamentCode{MapType: MapTypeSummonersRift, PickType: PickTypeBlindPick, SpectatorType: SpectatorTypeAll, TeamSize: 5, AllowedSummonerIDs: []int{1, 2}},
+		},
+		{
+			name: "Empty",
+			tc:   TournamentCode{},
+			want: []string{
+				"teamSize must be between 1 and 5, got 0",
+				`illegal mapType ""`,
+				`illegal pickType ""`,
+				`illegal spectatorType ""`,
+			},
+		},
+		{
+			name: "Allowed Summoners",
+			tc:   TournamentCode{MapType: MapTypeHowlingAbyss, PickType: PickTypeAllRandom, SpectatorType: SpectatorTypeNone, TeamSize: 1, AllowedSummonerIDs: []int{1, 1, -2}},
+			want: []string{
+				"duplicate allowedSummonerIds entry 1",
+				"illegal allowedSummonerIds entry -2",
+				"3 allowedSummonerIds do not fit in two teams of 1",
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.tc.Validate()
+			if tc.want == nil {
+				if err != nil {
+					t.Errorf("Validate returned unexpected error: %v", err)
+				}
+				return
+			}
+			verr, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("expected *ValidationError, got %v", err)
+			}
+			if !reflect.DeepEqual(verr.Problems, tc.want) {
+				t.Errorf("unexpected problems: got %q, want %q", verr.Problems, tc.want)
+			}
+		})
+	}
+}
+
+func TestTournamentCodeUpdateValidate(t *testing.T) {
+	if err := (&TournamentCodeUpdate{}).Validate(); err != nil {
+		t.Errorf("Validate returned unexpected error for empty update: %v", err)
+	}
+	err := (&TournamentCodeUpdate{MapType: "CRYSTAL_SCAR", PickType: PickTypeDraftMode, SpectatorType: "SOME"}).Validate()
+	want := `invalid tournament code update: illegal mapType "CRYSTAL_SCAR", illegal spectatorType "SOME"`
+	if err == nil || err.Error() != want {
+		t.Errorf("unexpected error: got %v, want %s", err, want)
+	}
+}
+
+func TestTournamentCodesOptionsValidate(t *testing.T) {
+	tt := []struct {
+		count int
+		valid bool
+	}{
+		{-1, false},
+		{0, true},
+		{1, true},
+		{MaxTournamentCodes, true},
+		{MaxTournamentCodes + 1, false},
+	}
+	for _, tc := range tt {
+		err := (&TournamentCodesOptions{Count: tc.count}).Validate()
+		if valid := err == nil; valid != tc.valid {
+			t.Errorf("Validate with Count %d: got error %v, want valid %v", tc.count, err, tc.valid)
+		}
+	}
+}
+
+func TestTournamentCodes_Validation(t *testing.T) {
+	client, mux, _, teardown := createTestServer()
+	defer teardown()
+
+	mux.HandleFunc("/lol/tournament/v3/codes", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request sent despite failing validation")
+	})
+
+	tc := &TournamentCode{MapType: MapTypeSummonersRift, PickType: PickTypeBlindPick, SpectatorType: SpectatorTypeAll, TeamSize: 5}
+	_, resp, err := client.Tournament.Codes(tc, func(o *TournamentCodesOptions) { o.Count = MaxTournamentCodes + 1 })
+	if _, ok := err.(*ValidationError); !ok {
+		t.Errorf("expected *ValidationError, got %v", err)
+	}
+	if resp != nil {
+		t.Errorf("expected nil response, got %v", resp)
+	}
+
+	// Problems with the code and the options are reported together.
+	_, _, err = client.Tournament.Codes(&TournamentCode{TeamSize: 5, MapType: MapTypeSummonersRift, PickType: "RANDOM", SpectatorType: SpectatorTypeAll}, func(o *TournamentCodesOptions) { o.Count = -1 })
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("expected *ValidationError, got %v", err)
+	}
+	want := []string{`illegal pickType "RANDOM"`, "count must be between 0 and 1000, where 0 creates a single code, got -1"}
+	if !reflect.DeepEqual(verr.Problems, want) {
+		t.Errorf("unexpected problems: got %q, want %q", verr.Problems, want)
+	}
+
+	if _, err := client.Tournament.UpdateTournament("CODE", &TournamentCodeUpdate{PickType: "RANDOM"}); err == nil {
+		t.Error("expected error for illegal pick type")
+	}
+}
+
+func TestTournamentRegistrationIDs(t *testing.T) {
+	client, mux, _, teardown := createTestServer()
+	defer teardown()
+
+	for path, id := range map[string]int{
+		"/lol/tournament/v3/providers":        11,
+		"/lol/tournament/v3/tournaments":      12,
+		"/lol/tournament-stub/v3/providers":   21,
+		"/lol/tournament-stub/v3/tournaments": 22,
+	} {
+		id := id
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, id)
+		})
+	}
+
+	tt := []struct {
+		name string
+		fn   func() (*int, *http.Response, error)
+		want int
+	}{
+		{"Tournament.Provider", func() (*int, *http.Response, error) { return client.Tournament.Provider(&ProviderRegistration{}) }, 11},
+		{"Tournament.Tournament", func() (*int, *http.Response, error) { return client.Tournament.Tournament(&TournamentRegistration{}) }, 12},
+		{"TournamentStub.Provider", func() (*int, *http.Response, error) { return client.TournamentStub.Provider(&ProviderRegistration{}) }, 21},
+		{"TournamentStub.Tournament", func() (*int, *http.Response, error) {
+			return client.TournamentStub.Tournament(&TournamentRegistration{})
+		}, 22},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, _, err := tc.fn()
+			if err != nil {
+				t.Fatalf("%s returned error: %v", tc.name, err)
+			}
+			if got == nil || *got != tc.want {
+				t.Errorf("%s = %v, want %d", tc.name, got, tc.want)
+			}
+		})
+	}
+}
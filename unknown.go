@@ -0,0 +1,224 @@
+package ionia
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WithUnknownFields returns a ClientOption which calls fn for each response that
+// contains JSON fields with no matching field in the DTO it was decoded into.
+// fn is passed the fields as reported by UnknownFields, and their values as
+// returned by Extras, so that they can be archived with the DTO. fn is called
+// from the goroutine which made the request.
+func WithUnknownFields(fn func(req *http.Request, fields []string, extras map[string]json.RawMessage)) ClientOption {
+	return func(c *Client) {
+		c.unknownFields = fn
+	}
+}
+
+// UnknownFields reports the fields in the JSON data which have no matching field
+// in v, such as a stat added to ParticipantStatsDTO since this package was updated.
+// Each field is reported once, by its path, with the indexes of arrays and the
+// keys of maps written as []:
+//
+//	participants[].stats.newStat
+func UnknownFields(data []byte, v interface{}) ([]string, error) {
+	var raw json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	walkUnknown(raw, reflect.TypeOf(v), "", "", func(_, generic string, _ json.RawMessage) {
+		seen[generic] = true
+	})
+	fields := make([]string, 0, len(seen))
+	for f := range seen {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+// Extras returns the raw values of the fields in the JSON data which have no
+// matching field in v, keyed by their path, so that they can be archived with
+// the decoded DTO. Indexes and map keys are included in the path:
+//
+//	participants[3].stats.newStat
+func Extras(data []byte, v interface{}) (map[string]json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	extras := make(map[string]json.RawMessage)
+	walkUnknown(raw, reflect.TypeOf(v), "", "", func(path, _ string, value json.RawMessage) {
+		extras[path] = value
+	})
+	return extras, nil
+}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// walkUnknown calls fn for each field of data which is not decoded into a value of
+// type t. path includes array indexes and map keys, while generic replaces them with [].
+func walkUnknown(data []byte, t reflect.Type, path, generic string, fn func(path, generic string, raw json.RawMessage)) {
+	if t == nil {
+		return
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if reflect.PtrTo(t).Implements(unmarshalerType) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(data, &fields) != nil {
+			return
+		}
+		known := jsonFields(t)
+		for name, raw := range fields {
+			p, g := joinPath(path, name), joinPath(generic, name)
+			ft, ok := fieldType(known, name)
+			if !ok {
+				fn(p, g, raw)
+				continue
+			}
+			walkUnknown(raw, ft, p, g, fn)
+		}
+	case reflect.Slice, reflect.Array:
+		var elems []json.RawMessage
+		if json.Unmarshal(data, &elems) != nil {
+			return
+		}
+		for i, raw := range elems {
+			walkUnknown(raw, t.Elem(), path+"["+strconv.Itoa(i)+"]", generic+"[]", fn)
+		}
+	case reflect.Map:
+		var elems map[string]json.RawMessage
+		if json.Unmarshal(data, &elems) != nil {
+			return
+		}
+		for k, raw := range elems {
+			walkUnknown(raw, t.Elem(), path+"["+k+"]", generic+"[]", fn)
+		}
+	}
+}
+
+// jsonField is a field of a struct which encoding/json decodes into.
+type jsonField struct {
+	name   string
+	tagged bool
+	depth  int
+	typ    reflect.Type
+}
+
+// jsonFields returns the fields of struct type t which encoding/json decodes
+// into, including those of embedded structs. As in encoding/json, a field hides
+// deeper fields with the same name, and fields with the same name at the same
+// depth are all ignored, unless exactly one of them is tagged.
+func jsonFields(t reflect.Type) []jsonField {
+	var all []jsonField
+	visited := make(map[reflect.Type]bool)
+	for depth, current := 0, []reflect.Type{t}; len(current) > 0; depth++ {
+		var next []reflect.Type
+		for _, st := range current {
+			if visited[st] {
+				continue
+			}
+			visited[st] = true
+			for i := 0; i < st.NumField(); i++ {
+				f := st.Field(i)
+				tag := f.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name := strings.Split(tag, ",")[0]
+				if f.Anonymous && name == "" {
+					ft := f.Type
+					if ft.Kind() == reflect.Ptr {
+						ft = ft.Elem()
+					}
+					if ft.Kind() == reflect.Struct {
+						next = append(next, ft)
+						continue
+					}
+				}
+				if f.PkgPath != "" {
+					continue
+				}
+				tagged := name != ""
+				if !tagged {
+					name = f.Name
+				}
+				all = append(all, jsonField{name, tagged, depth, f.Type})
+			}
+		}
+		current = next
+	}
+
+	byName := make(map[string][]jsonField)
+	for _, f := range all {
+		byName[f.name] = append(byName[f.name], f)
+	}
+	fields := make([]jsonField, 0, len(all))
+	for _, f := range all {
+		if dominant, ok := dominantField(byName[f.name]); ok && dominant == f {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// dominantField returns the field which encoding/json decodes a name into, of
+// fields with that name ordered by depth.
+func dominantField(fields []jsonField) (jsonField, bool) {
+	var dominant []jsonField
+	for _, f := range fields {
+		if f.depth > fields[0].depth {
+			break
+		}
+		dominant = append(dominant, f)
+	}
+	if len(dominant) == 1 {
+		return dominant[0], true
+	}
+	var tagged []jsonField
+	for _, f := range dominant {
+		if f.tagged {
+			tagged = append(tagged, f)
+		}
+	}
+	if len(tagged) == 1 {
+		return tagged[0], true
+	}
+	return jsonField{}, false
+}
+
+// fieldType returns the type of the field which a JSON field with the given
+// name is decoded into, matching names as encoding/json does.
+func fieldType(fields []jsonField, name string) (reflect.Type, bool) {
+	for _, f := range fields {
+		if f.name == name {
+			return f.typ, true
+		}
+	}
+	for _, f := range fields {
+		if strings.EqualFold(f.name, name) {
+			return f.typ, true
+		}
+	}
+	return nil, false
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,142 @@
+package ionia
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const unknownMatchJSON = `{
+	"gameId": 1,
+	"GAMEMODE": "CLASSIC",
+	"arenaRound": 3,
+	"participants": [
+		{"participantId": 1, "stats": {"kills": 2, "feats": 1}},
+		{"participantId": 2, "stats": {"kills": 0, "feats": 0}, "augments": [1, 2]}
+	],
+	"teams": [{"teamId": 100, "bans": [{"championId": 17, "pickTurn": 1, "source": "vote"}]}]
+}`
+
+type unknownInner struct {
+	Stats struct {
+		Kills int `json:"kills"`
+	} `json:"stats"`
+	Role string `json:"role"`
+}
+
+type unknownOuter struct {
+	unknownInner
+	// Hides the embedded stats, as the shallower field.
+	Stats struct {
+		Deaths int `json:"deaths"`
+	} `json:"stats"`
+}
+
+func TestUnknownFields(t *testing.T) {
+	tt := []struct {
+		name string
+		data string
+		v    interface{}
+		want []string
+	}{
+		{"Known", `{"id": 1, "name": "Faker"}`, &SummonerDTO{}, []string{}},
+		{
+			name: "Nested",
+			data: unknownMatchJSON,
+			v:    &MatchDTO{},
+			want: []string{"arenaRound", "participants[].augments", "participants[].stats.feats", "teams[].bans[].source"},
+		},
+		{
+			name: "Map",
+			data: `{"data": {"Annie": {"id": 1, "roles": ["mage"]}, "Zed": {"id": 238}}, "version": "8.10.1"}`,
+			v:    &StaticChampionListDTO{},
+			want: []string{"data[].roles"},
+		},
+		{
+			name: "Embedded",
+			data: `{"stats": {"kills": 1, "deaths": 2}, "role": "mid"}`,
+			v:    &unknownOuter{},
+			want: []string{"stats.kills"},
+		},
+		{"Untyped", `{"a": {"b": 1}}`, &map[string]interface{}{}, []string{}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := UnknownFields([]byte(tc.data), tc.v)
+			if err != nil {
+				t.Fatalf("UnknownFields returned error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("UnknownFields = %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := UnknownFields([]byte(`{"id":`), &SummonerDTO{}); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestExtras(t *testing.T) {
+	got, err := Extras([]byte(unknownMatchJSON), &MatchDTO{})
+	if err != nil {
+		t.Fatalf("Extras returned error: %v", err)
+	}
+	want := map[string]string{
+		"arenaRound":                  `3`,
+		"participants[0].stats.feats": `1`,
+		"participants[1].stats.feats": `0`,
+		"participants[1].augments":    `[1, 2]`,
+		"teams[0].bans[0].source":     `"vote"`,
+	}
+	if len(got) != len(want) {
+		t.Errorf("expected %d extras, got %d: %v", len(want), len(got), got)
+	}
+	for k, v := range want {
+		if string(got[k]) != v {
+			t.Errorf("Extras[%q] = %s, want %s", k, got[k], v)
+		}
+	}
+}
+
+func TestWithUnknownFields(t *testing.T) {
+	client, mux, _, teardown := createTestServer()
+	defer teardown()
+
+	var reported []string
+	var extras map[string]json.RawMessage
+	WithUnknownFields(func(req *http.Request, fields []string, e map[string]json.RawMessage) {
+		reported = append(reported, fields...)
+		extras = e
+	})(client)
+
+	mux.HandleFunc("/lol/match/v3/matches/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, unknownMatchJSON)
+	})
+	mux.HandleFunc("/lol/summoner/v3/summoners/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 1}`)
+	})
+
+	m, _, err := client.Match.MatchByID(1)
+	if err != nil {
+		t.Fatalf("Match.MatchByID returned error: %v", err)
+	}
+	if m.GameID != 1 || m.GameMode != "CLASSIC" || len(m.Participants) != 2 {
+		t.Errorf("unexpected match: %+v", m)
+	}
+	if len(reported) != 4 {
+		t.Errorf("expected 4 unknown fields, got %v", reported)
+	}
+	if got := string(extras["participants[1].augments"]); got != "[1, 2]" {
+		t.Errorf("expected the value of participants[1].augments, got %q", got)
+	}
+
+	reported = nil
+	client.Summoner.BySummonerID(1)
+	if reported != nil {
+		t.Errorf("expected no unknown fields, got %v", reported)
+	}
+}